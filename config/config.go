@@ -0,0 +1,174 @@
+package config
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+)
+
+var (
+	tokenSecret     []byte
+	tokenSecretOnce sync.Once
+)
+
+// AdminKey gets the key required to signup an Admin account, from the
+// environment. Admin signup is disabled when it is not set.
+func AdminKey() string {
+	return os.Getenv("ADMIN_KEY")
+}
+
+// TokenSecret gets the key tokens are signed with, from the TOKEN_SECRET
+// environment variable. When it is not set a random key is used, and tokens
+// stop working when the service restarts.
+func TokenSecret() []byte {
+	tokenSecretOnce.Do(func() {
+		tokenSecret = []byte(os.Getenv("TOKEN_SECRET"))
+
+		if len(tokenSecret) == 0 {
+			log.Println("TOKEN_SECRET is not set, tokens will not survive a restart")
+
+			tokenSecret = make([]byte, 32)
+			if _, err := rand.Read(tokenSecret); err != nil {
+				log.Fatal(err)
+			}
+		}
+	})
+
+	return tokenSecret
+}
+
+// HoldMinutes gets the number of minutes a slot hold lasts, from the
+// environment. defaults to 10.
+func HoldMinutes() int {
+	return envInt("HOLD_MINUTES", 10)
+}
+
+// IdempotencyKeyHours gets for how many hours responses are kept for replay
+// to requests repeating an Idempotency-Key, from the IDEMPOTENCY_KEY_HOURS
+// environment variable. defaults to 24.
+func IdempotencyKeyHours() int {
+	return envInt("IDEMPOTENCY_KEY_HOURS", 24)
+}
+
+// PatientOverlapCheck gets whether Patients are stopped from booking
+// appointments that overlap their own, from the PATIENT_OVERLAP_CHECK
+// environment variable. defaults to true.
+func PatientOverlapCheck() bool {
+	return envBool("PATIENT_OVERLAP_CHECK", true)
+}
+
+// MaxFutureAppointments gets how many upcoming appointments a Patient can
+// have, from the MAX_FUTURE_APPOINTMENTS environment variable. 0, the
+// default, means no limit.
+func MaxFutureAppointments() int {
+	return envInt("MAX_FUTURE_APPOINTMENTS", 0)
+}
+
+// MaxFutureAppointmentsPerDoctor gets how many upcoming appointments a
+// Patient can have with one Doctor, from the
+// MAX_FUTURE_APPOINTMENTS_PER_DOCTOR environment variable. 0, the default,
+// means no limit.
+func MaxFutureAppointmentsPerDoctor() int {
+	return envInt("MAX_FUTURE_APPOINTMENTS_PER_DOCTOR", 0)
+}
+
+// MaxAppointmentsPerDay gets how many appointments a Patient can have on one
+// day, from the MAX_APPOINTMENTS_PER_DAY environment variable. 0, the
+// default, means no limit.
+func MaxAppointmentsPerDay() int {
+	return envInt("MAX_APPOINTMENTS_PER_DAY", 0)
+}
+
+// NoShowGraceMinutes gets how many minutes after the start an appointment
+// nobody checked in to is marked as a no-show, from the
+// NO_SHOW_GRACE_MINUTES environment variable. defaults to 30.
+func NoShowGraceMinutes() int {
+	return envInt("NO_SHOW_GRACE_MINUTES", 30)
+}
+
+// NoShowSweepMinutes gets how often appointments are checked for no-shows,
+// from the NO_SHOW_SWEEP_MINUTES environment variable. 0 disables the check.
+// defaults to 5.
+func NoShowSweepMinutes() int {
+	return envInt("NO_SHOW_SWEEP_MINUTES", 5)
+}
+
+// RequestSweepMinutes gets how often booking requests are checked for expiry,
+// from the REQUEST_SWEEP_MINUTES environment variable. 0 disables the check.
+// defaults to 1.
+func RequestSweepMinutes() int {
+	return envInt("REQUEST_SWEEP_MINUTES", 1)
+}
+
+// HoldSweepMinutes gets how often slot holds are checked for expiry, from the
+// HOLD_SWEEP_MINUTES environment variable. 0 disables the check. defaults to
+// 1.
+func HoldSweepMinutes() int {
+	return envInt("HOLD_SWEEP_MINUTES", 1)
+}
+
+// NoShowLimit gets after how many no-shows within NoShowPeriodDays a Patient
+// is restricted, from the NO_SHOW_LIMIT environment variable. 0, the
+// default, means no restrictions.
+func NoShowLimit() int {
+	return envInt("NO_SHOW_LIMIT", 0)
+}
+
+// NoShowPeriodDays gets the number of days no-shows are counted over, from
+// the NO_SHOW_PERIOD_DAYS environment variable. defaults to 90.
+func NoShowPeriodDays() int {
+	return envInt("NO_SHOW_PERIOD_DAYS", 90)
+}
+
+// NoShowCooldownDays gets for how many days after their last no-show a
+// restricted Patient cannot book, from the NO_SHOW_COOLDOWN_DAYS environment
+// variable. defaults to 0.
+func NoShowCooldownDays() int {
+	return envInt("NO_SHOW_COOLDOWN_DAYS", 0)
+}
+
+// NoShowDeposit gets whether appointments of a restricted Patient require a
+// deposit, from the NO_SHOW_DEPOSIT environment variable. defaults to false.
+func NoShowDeposit() bool {
+	return envBool("NO_SHOW_DEPOSIT", false)
+}
+
+// envInt gets a non-negative integer from the environment variable name,
+// falling back to def when it is not set or invalid.
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+
+	if len(value) == 0 {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Invalid value %q for %s, defaulting to %d\n", value, name, def)
+
+		return def
+	}
+
+	return n
+}
+
+// envBool gets a boolean from the environment variable name, falling back to
+// def when it is not set or invalid.
+func envBool(name string, def bool) bool {
+	value := os.Getenv(name)
+
+	if len(value) == 0 {
+		return def
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid value %q for %s, defaulting to %t\n", value, name, def)
+
+		return def
+	}
+
+	return b
+}
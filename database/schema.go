@@ -0,0 +1,8 @@
+package database
+
+import (
+	_ "embed"
+)
+
+//go:embed schema.sql
+var Schema string
@@ -1,16 +1,22 @@
 CREATE TABLE IF NOT EXISTS `doctor` (
   `id` INTEGER PRIMARY KEY,
   `name` VARCHAR(100) NULL,
+  `region` VARCHAR(100) NOT NULL DEFAULT '',
+  `specialty` VARCHAR(100) NOT NULL DEFAULT '',
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE UNIQUE INDEX IF NOT EXISTS `doctor_name_UNIQUE` ON `doctor` (`name` ASC);
 
+CREATE INDEX IF NOT EXISTS `doctor_specialty_INDEX` ON `doctor` (`specialty` COLLATE NOCASE ASC);
+
 CREATE TABLE IF NOT EXISTS `doctor_schedule` (
   `id` INTEGER PRIMARY KEY,
   `doctor_id` INT NOT NULL,
   `start_time` TIMESTAMP NULL,
   `end_time` TIMESTAMP NULL,
+  `capacity` INT NOT NULL DEFAULT 1,
+  `appointment_type` VARCHAR(100) NOT NULL DEFAULT '',
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -19,10 +25,14 @@ CREATE INDEX IF NOT EXISTS `schedule_doctor_id_INDEX` ON `doctor_schedule` (`doc
 CREATE TABLE IF NOT EXISTS `patient` (
   `id` INTEGER PRIMARY KEY,
   `name` VARCHAR(100) NULL,
+  `account_id` INT NULL,
+  `contact` VARCHAR(255) NOT NULL DEFAULT '',
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS `patient_name_UNIQUE` ON `patient` (`name` ASC);
+CREATE UNIQUE INDEX IF NOT EXISTS `patient_name_UNIQUE` ON `patient` (`name` ASC) WHERE `account_id` IS NULL;
+
+CREATE INDEX IF NOT EXISTS `patient_account_id_INDEX` ON `patient` (`account_id` ASC);
 
 CREATE TABLE IF NOT EXISTS `appointments` (
   `id` INTEGER PRIMARY KEY,
@@ -31,7 +41,182 @@ CREATE TABLE IF NOT EXISTS `appointments` (
   `start_time` TIMESTAMP NOT NULL,
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `deleted_at` TIMESTAMP NULL,
-  `is_active` INT
+  `is_active` INT,
+  `seat` INT NOT NULL DEFAULT 1,
+  `duration_minutes` INT NOT NULL DEFAULT 15,
+  `booked_by` INT NULL,
+  `reason` VARCHAR(500) NOT NULL DEFAULT '',
+  `status` VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+  `confirmed_at` TIMESTAMP NULL,
+  `checked_in_at` TIMESTAMP NULL,
+  `started_at` TIMESTAMP NULL,
+  `completed_at` TIMESTAMP NULL,
+  `no_show_at` TIMESTAMP NULL,
+  `cancellation_reason` VARCHAR(500) NOT NULL DEFAULT '',
+  `cancelled_by` INT NULL,
+  `cancelled_by_type` VARCHAR(20) NULL,
+  `late_cancellation` INT NOT NULL DEFAULT 0,
+  `deposit_required` INT NOT NULL DEFAULT 0,
+  `series_id` INT NULL,
+  `initiated_by` INT NULL,
+  `initiated_by_type` VARCHAR(20) NULL,
+  `request_expires_at` TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS `patient_id_active_st_INDEX` ON `appointments` (`patient_id` ASC, `is_active` ASC, `start_time` ASC);
+
+CREATE INDEX IF NOT EXISTS `doctor_id_active_st_INDEX` ON `appointments` (`doctor_id` ASC, `is_active` ASC, `start_time` ASC);
+
+CREATE INDEX IF NOT EXISTS `series_id_st_INDEX` ON `appointments` (`series_id` ASC, `start_time` ASC);
+
+CREATE INDEX IF NOT EXISTS `status_st_INDEX` ON `appointments` (`status` ASC, `start_time` ASC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `appointments_active_seat_UNIQUE` ON `appointments` (`doctor_id` ASC, `start_time` ASC, `seat` ASC) WHERE `is_active`=1;
+
+CREATE TABLE IF NOT EXISTS `appointment_series` (
+  `id` INTEGER PRIMARY KEY,
+  `doctor_id` INT NOT NULL,
+  `patient_id` INT NOT NULL,
+  `booked_by` INT NOT NULL,
+  `interval_days` INT NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS `admin` (
+  `id` INTEGER PRIMARY KEY,
+  `name` VARCHAR(100) NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `admin_name_UNIQUE` ON `admin` (`name` ASC);
+
+CREATE TABLE IF NOT EXISTS `holiday` (
+  `id` INTEGER PRIMARY KEY,
+  `region` VARCHAR(100) NOT NULL,
+  `holiday_date` VARCHAR(10) NOT NULL,
+  `name` VARCHAR(255) NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `holiday_region_date_UNIQUE` ON `holiday` (`region` ASC, `holiday_date` ASC);
+
+CREATE TABLE IF NOT EXISTS `doctor_holiday_optin` (
+  `id` INTEGER PRIMARY KEY,
+  `doctor_id` INT NOT NULL,
+  `holiday_date` VARCHAR(10) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `optin_doctor_date_UNIQUE` ON `doctor_holiday_optin` (`doctor_id` ASC, `holiday_date` ASC);
+
+CREATE TABLE IF NOT EXISTS `doctor_settings` (
+  `doctor_id` INTEGER PRIMARY KEY,
+  `min_notice_minutes` INT NOT NULL DEFAULT 0,
+  `max_advance_days` INT NOT NULL DEFAULT 0,
+  `cancellation_cutoff_minutes` INT NOT NULL DEFAULT 0,
+  `require_cancellation_reason` INT NOT NULL DEFAULT 0,
+  `approval_required` INT NOT NULL DEFAULT 0,
+  `request_expiry_hours` INT NOT NULL DEFAULT 24,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX IF NOT EXISTS `doctor_id_active_st_INDEX` ON `appointments` (`doctor_id` ASC, `is_active` ASC, `start_time` ASC);
\ No newline at end of file
+CREATE TABLE IF NOT EXISTS `appointment_history` (
+  `id` INTEGER PRIMARY KEY,
+  `appointment_id` INT NOT NULL,
+  `event` VARCHAR(50) NOT NULL,
+  `doctor_id` INT NOT NULL,
+  `start_time` TIMESTAMP NOT NULL,
+  `actor_id` INT NOT NULL,
+  `actor_type` VARCHAR(20) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `history_appointment_id_INDEX` ON `appointment_history` (`appointment_id` ASC);
+
+CREATE TABLE IF NOT EXISTS `waitlist` (
+  `id` INTEGER PRIMARY KEY,
+  `doctor_id` INT NOT NULL,
+  `patient_id` INT NOT NULL,
+  `start_time` TIMESTAMP NULL,
+  `waitlist_date` VARCHAR(10) NOT NULL,
+  `auto_book` INT NOT NULL DEFAULT 0,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'waiting',
+  `appointment_id` INT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `waitlist_doctor_date_status_INDEX` ON `waitlist` (`doctor_id` ASC, `waitlist_date` ASC, `status` ASC);
+
+CREATE TABLE IF NOT EXISTS `notification` (
+  `id` INTEGER PRIMARY KEY,
+  `patient_id` INT NOT NULL,
+  `message` VARCHAR(500) NOT NULL,
+  `is_read` INT NOT NULL DEFAULT 0,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `notification_patient_id_INDEX` ON `notification` (`patient_id` ASC);
+
+CREATE TABLE IF NOT EXISTS `slot_hold` (
+  `id` INTEGER PRIMARY KEY,
+  `doctor_id` INT NOT NULL,
+  `patient_id` INT NOT NULL,
+  `start_time` TIMESTAMP NOT NULL,
+  `seat` INT NOT NULL DEFAULT 1,
+  `expires_at` TIMESTAMP NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'held',
+  `appointment_id` INT NULL,
+  `waitlist_id` INT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `hold_doctor_st_status_INDEX` ON `slot_hold` (`doctor_id` ASC, `start_time` ASC, `status` ASC);
+
+CREATE INDEX IF NOT EXISTS `hold_status_expires_at_INDEX` ON `slot_hold` (`status` ASC, `expires_at` ASC);
+
+CREATE TABLE IF NOT EXISTS `delegate` (
+  `id` INTEGER PRIMARY KEY,
+  `doctor_id` INT NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `delegate_doctor_name_UNIQUE` ON `delegate` (`doctor_id` ASC, `name` ASC);
+
+CREATE TABLE IF NOT EXISTS `appointment_note` (
+  `id` INTEGER PRIMARY KEY,
+  `appointment_id` INT NOT NULL,
+  `author_id` INT NOT NULL,
+  `author_type` VARCHAR(20) NOT NULL,
+  `note` TEXT NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `note_appointment_id_INDEX` ON `appointment_note` (`appointment_id` ASC);
+
+CREATE TABLE IF NOT EXISTS `idempotency_key` (
+  `id` INTEGER PRIMARY KEY,
+  `idempotency_key` VARCHAR(255) NOT NULL,
+  `path` VARCHAR(100) NOT NULL,
+  `request_hash` VARCHAR(64) NOT NULL,
+  `status_code` INT NOT NULL DEFAULT 0,
+  `response` BLOB NULL,
+  `location` VARCHAR(255) NOT NULL DEFAULT '',
+  `created_at` TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `idempotency_key_path_UNIQUE` ON `idempotency_key` (`idempotency_key` ASC, `path` ASC);
+
+CREATE INDEX IF NOT EXISTS `idempotency_created_at_INDEX` ON `idempotency_key` (`created_at` ASC);
+
+CREATE TABLE IF NOT EXISTS `calendar_feed` (
+  `id` INTEGER PRIMARY KEY,
+  `user_id` INT NOT NULL,
+  `user_type` VARCHAR(20) NOT NULL,
+  `secret` VARCHAR(64) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `calendar_feed_user_UNIQUE` ON `calendar_feed` (`user_id` ASC, `user_type` ASC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `calendar_feed_secret_UNIQUE` ON `calendar_feed` (`secret` ASC);
@@ -0,0 +1,6 @@
+package domain
+
+type Admin struct {
+	ID   int    `json:"adminId"`
+	Name string `json:"name"`
+}
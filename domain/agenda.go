@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	AgendaAppointment = "appointment"
+	AgendaGap         = "gap"
+	AgendaBlocked     = "blocked"
+)
+
+// Agenda is the day of the Doctor in order of start time.
+type Agenda struct {
+	DoctorID int           `json:"doctorid"`
+	Date     string        `json:"date"`
+	Entries  []AgendaEntry `json:"entries"`
+}
+
+// AgendaEntry is an appointment of the Doctor, or a gap in their schedule
+// nobody booked, or schedule time blocked for the reason in BlockedBy, e.g. a
+// holiday.
+type AgendaEntry struct {
+	Type          string    `json:"type"`
+	StartTime     time.Time `json:"starttime"`
+	EndTime       time.Time `json:"endtime"`
+	AppointmentID int       `json:"appointmentid,omitempty"`
+	Patient       *Patient  `json:"patient,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Notes         []Note    `json:"notes,omitempty"`
+	BlockedBy     string    `json:"blockedby,omitempty"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// GetAgenda returns the appointments of the Doctor starting between from and
+// to with their Patients and notes, in order of start time. Dependents are
+// reached through the contact of their account unless they have their own.
+func (ar *apptRepo) GetAgenda(doctorID int, from time.Time, to time.Time) ([]AgendaEntry, errors.AppointmentErr) {
+	entries := make([]AgendaEntry, 0)
+
+	from, to = from.UTC(), to.UTC()
+
+	query := "SELECT a.id, a.start_time, a.duration_minutes, a.reason, a.status, p.id, p.name, COALESCE(p.account_id, 0), CASE WHEN p.contact<>'' THEN p.contact ELSE COALESCE(acct.contact, '') END FROM appointments a JOIN patient p ON p.id=a.patient_id LEFT JOIN patient acct ON acct.id=p.account_id WHERE a.doctor_id=? AND a.is_active=1 AND a.start_time>=? AND a.start_time<? ORDER BY a.start_time, a.id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when preparing statement to fetch agenda", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(doctorID, from, to)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when executing statement to fetch agenda", err)
+	}
+	defer rows.Close()
+
+	byAppointment := make(map[int]int)
+
+	for rows.Next() {
+		entry := AgendaEntry{Type: AgendaAppointment, Patient: &Patient{}}
+		var duration int
+
+		if err := rows.Scan(&entry.AppointmentID, &entry.StartTime, &duration, &entry.Reason, &entry.Status, &entry.Patient.ID, &entry.Patient.Name, &entry.Patient.AccountID, &entry.Patient.Contact); err != nil {
+			return entries, errors.NewInternalServerError("error occured when parsing agenda", err)
+		}
+
+		entry.EndTime = entry.StartTime.Add(time.Duration(duration) * time.Minute)
+		entry.Notes = make([]Note, 0)
+
+		byAppointment[entry.AppointmentID] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	// Notes of all the appointments in one query
+	query = "SELECT n.id, n.appointment_id, n.author_id, n.author_type, n.note, n.created_at FROM appointment_note n JOIN appointments a ON a.id=n.appointment_id WHERE a.doctor_id=? AND a.is_active=1 AND a.start_time>=? AND a.start_time<? ORDER BY n.id;"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when preparing statement to fetch agenda notes", err)
+	}
+	defer stmt.Close()
+
+	rows, err = stmt.Query(doctorID, from, to)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when executing statement to fetch agenda notes", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note Note
+
+		if err := rows.Scan(&note.ID, &note.AppointmentID, &note.AuthorID, &note.AuthorType, &note.Note, &note.CreatedAt); err != nil {
+			return entries, errors.NewInternalServerError("error occured when parsing agenda notes", err)
+		}
+
+		if i, ok := byAppointment[note.AppointmentID]; ok {
+			entries[i].Notes = append(entries[i].Notes, note)
+		}
+	}
+
+	return entries, nil
+}
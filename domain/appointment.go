@@ -4,10 +4,21 @@ import (
 	"time"
 )
 
+// SlotMinutes is the length of a slot of the Doctor schedule.
+const SlotMinutes = 15
+
+// Appointment is a slot of the Doctor schedule. Booked is set once the slot
+// is full, with no capacity remaining. Status is the status of the appointment
+// in single patient slots.
 type Appointment struct {
-	ID        string    `json:"appointmentid"`
-	DoctorID  string    `json:"doctorid"`
-	PatientID string    `json:"patientid"`
-	StartTime time.Time `json:"starttime"`
-	Booked    bool      `json:"booked"`
+	ID              string    `json:"appointmentid"`
+	DoctorID        string    `json:"doctorid"`
+	DoctorName      string    `json:"doctorname,omitempty"`
+	PatientID       string    `json:"patientid"`
+	StartTime       time.Time `json:"starttime"`
+	Booked          bool      `json:"booked"`
+	Capacity        int       `json:"capacity"`
+	Remaining       int       `json:"remaining"`
+	AppointmentType string    `json:"appointmenttype"`
+	Status          string    `json:"status,omitempty"`
 }
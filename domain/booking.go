@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// Booking is an appointment of a Patient with a Doctor, with who booked it
+// and when it entered each Status. AccountID is the account managing the
+// Patient, if the Patient is a dependent.
+type Booking struct {
+	ID              int        `json:"appointmentid"`
+	DoctorID        int        `json:"doctorid"`
+	PatientID       int        `json:"patientid"`
+	BookedBy        int        `json:"bookedby"`
+	AccountID       int        `json:"-"`
+	StartTime       time.Time  `json:"starttime"`
+	DurationMinutes int        `json:"durationminutes"`
+	Active          bool       `json:"active"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	RequestedAt     time.Time  `json:"requestedat"`
+	ConfirmedAt     *time.Time `json:"confirmedat,omitempty"`
+	CheckedInAt     *time.Time `json:"checkedinat,omitempty"`
+	StartedAt       *time.Time `json:"startedat,omitempty"`
+	CompletedAt     *time.Time `json:"completedat,omitempty"`
+	CancelledAt     *time.Time `json:"cancelledat,omitempty"`
+	NoShowAt        *time.Time `json:"noshowat,omitempty"`
+
+	CancellationReason string `json:"cancellationreason,omitempty"`
+	CancelledBy        int    `json:"cancelledby,omitempty"`
+	CancelledByType    string `json:"cancelledbytype,omitempty"`
+	LateCancellation   bool   `json:"latecancellation,omitempty"`
+
+	DepositRequired bool `json:"depositrequired,omitempty"`
+	SeriesID        int  `json:"seriesid,omitempty"`
+
+	DoctorInitiated bool   `json:"doctorinitiated"`
+	InitiatedBy     int    `json:"initiatedby,omitempty"`
+	InitiatedByType string `json:"initiatedbytype,omitempty"`
+
+	RequestExpiresAt *time.Time `json:"requestexpiresat,omitempty"`
+}
+
+// ManagedBy checks if the Patient account can act on the appointment, being
+// its Patient, the account that booked it or the account of the dependent.
+func (b Booking) ManagedBy(patientID int) bool {
+	return patientID == b.PatientID || patientID == b.BookedBy || (b.AccountID != 0 && patientID == b.AccountID)
+}
+
+// EndTime is when the appointment is over.
+func (b Booking) EndTime() time.Time {
+	return b.StartTime.Add(time.Duration(b.DurationMinutes) * time.Minute)
+}
+
+// Open checks if the appointment has not taken place yet and can still be
+// cancelled or moved.
+func (b Booking) Open() bool {
+	return b.Status == StatusRequested || b.Status == StatusConfirmed
+}
+
+// AccountBooking is an appointment of a Patient account or its dependents,
+// with the Doctor seeing them.
+type AccountBooking struct {
+	Booking
+	Doctor Doctor `json:"doctor"`
+}
+
+// BookingFilter filters the appointments of the Patient account AccountID
+// and its dependents. Zero values match everything. Limit and Offset select
+// the page, in order of start time, latest first if Descending.
+type BookingFilter struct {
+	AccountID  int
+	PatientID  int
+	From       time.Time
+	To         time.Time
+	Statuses   []string
+	Descending bool
+	Limit      int
+	Offset     int
+}
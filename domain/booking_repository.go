@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"appointment/errors"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+func (ar *apptRepo) GetBooking(appointmentID int) (Booking, errors.AppointmentErr) {
+	booking := Booking{ID: appointmentID}
+
+	query := "SELECT " + bookingColumns + " FROM appointments a LEFT JOIN patient p ON p.id=a.patient_id WHERE a.id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return booking, errors.NewInternalServerError("error occured when preparing statement to fetch appointment", err)
+	}
+	defer stmt.Close()
+
+	if err = scanBooking(stmt.QueryRow(appointmentID), &booking); err != nil {
+		return booking, errors.NewNotFoundError(fmt.Sprintf("appointment id %d does not exist in database", appointmentID), err)
+	}
+
+	return booking, nil
+}
+
+// GetAccountBookings returns the page of appointments of the Patient account
+// and its dependents matching the filter, with the Doctor of each, and the
+// number of matching appointments across all pages.
+func (ar *apptRepo) GetAccountBookings(filter BookingFilter) ([]AccountBooking, int, errors.AppointmentErr) {
+	bookings := make([]AccountBooking, 0)
+
+	where := " FROM appointments a JOIN doctor d ON d.id=a.doctor_id LEFT JOIN patient p ON p.id=a.patient_id WHERE (a.patient_id=? OR a.booked_by=? OR p.account_id=?)"
+	args := []interface{}{filter.AccountID, filter.AccountID, filter.AccountID}
+
+	if filter.PatientID != 0 {
+		where += " AND a.patient_id=?"
+		args = append(args, filter.PatientID)
+	}
+
+	if !filter.From.IsZero() {
+		where += " AND a.start_time>=?"
+		args = append(args, filter.From.UTC())
+	}
+
+	if !filter.To.IsZero() {
+		where += " AND a.start_time<?"
+		args = append(args, filter.To.UTC())
+	}
+
+	if len(filter.Statuses) != 0 {
+		where += " AND a.status IN (" + placeholders(len(filter.Statuses)) + ")"
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+
+	var total int
+
+	if err := ar.db.QueryRow("SELECT COUNT(a.id)"+where+";", args...).Scan(&total); err != nil {
+		return bookings, 0, errors.NewInternalServerError("error occured when counting Patient appointments", err)
+	}
+
+	order := " ORDER BY a.start_time, a.id"
+	if filter.Descending {
+		order = " ORDER BY a.start_time DESC, a.id DESC"
+	}
+
+	query := "SELECT " + bookingColumns + ", d.id, d.name, d.region, d.specialty" + where + order + " LIMIT ? OFFSET ?;"
+	args = append(args, filter.Limit, filter.Offset)
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return bookings, total, errors.NewInternalServerError("error occured when preparing statement to fetch Patient appointments", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return bookings, total, errors.NewInternalServerError("error occured when executing statement to fetch Patient appointments", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking AccountBooking
+
+		if err := scanBooking(rows, &booking.Booking, &booking.Doctor.ID, &booking.Doctor.Name, &booking.Doctor.Region, &booking.Doctor.Specialty); err != nil {
+			return bookings, total, errors.NewInternalServerError("error occured when parsing Patient appointments", err)
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, total, nil
+}
+
+// bookingColumns are the columns of an appointment read by scanBooking, with
+// the appointments table as a and the patient table as p.
+const bookingColumns = "a.id, a.doctor_id, a.patient_id, COALESCE(a.booked_by, a.patient_id), COALESCE(p.account_id, 0), a.start_time, a.duration_minutes, a.is_active, a.reason, a.status, a.created_at, a.confirmed_at, a.checked_in_at, a.started_at, a.completed_at, a.deleted_at, a.no_show_at, a.cancellation_reason, COALESCE(a.cancelled_by, 0), COALESCE(a.cancelled_by_type, ''), a.late_cancellation, a.deposit_required, COALESCE(a.series_id, 0), COALESCE(a.initiated_by, 0), COALESCE(a.initiated_by_type, ''), a.request_expires_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBooking reads the bookingColumns into booking, and the columns selected
+// after them into extra.
+func scanBooking(row scanner, booking *Booking, extra ...interface{}) error {
+	var activeStatus int
+
+	dest := []interface{}{&booking.ID, &booking.DoctorID, &booking.PatientID, &booking.BookedBy, &booking.AccountID, &booking.StartTime, &booking.DurationMinutes, &activeStatus, &booking.Reason, &booking.Status, &booking.RequestedAt, &booking.ConfirmedAt, &booking.CheckedInAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt, &booking.NoShowAt, &booking.CancellationReason, &booking.CancelledBy, &booking.CancelledByType, &booking.LateCancellation, &booking.DepositRequired, &booking.SeriesID, &booking.InitiatedBy, &booking.InitiatedByType, &booking.RequestExpiresAt}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	booking.Active = activeStatus == 1
+	booking.DoctorInitiated = len(booking.InitiatedByType) != 0
+
+	return nil
+}
+
+// GetPatientBookings returns the active appointments of the Patient starting
+// between from and to, in order of start time.
+func (ar *apptRepo) GetPatientBookings(patientID int, from time.Time, to time.Time) ([]Booking, errors.AppointmentErr) {
+	bookings := make([]Booking, 0)
+
+	query := "SELECT id, doctor_id, patient_id, COALESCE(booked_by, patient_id), start_time, duration_minutes, reason, status FROM appointments WHERE patient_id=? AND is_active=1 AND start_time>=? AND start_time<? ORDER BY start_time;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when preparing statement to fetch Patient appointments", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(patientID, from.UTC(), to.UTC())
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when executing statement to fetch Patient appointments", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		booking := Booking{Active: true}
+
+		if err := rows.Scan(&booking.ID, &booking.DoctorID, &booking.PatientID, &booking.BookedBy, &booking.StartTime, &booking.DurationMinutes, &booking.Reason, &booking.Status); err != nil {
+			return bookings, errors.NewInternalServerError("error occured when parsing Patient appointments", err)
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// RescheduleAppointment moves an active appointment to a seat of another slot
+// within a transaction, keeping its ID. The previous slot is recorded in the
+// appointment history.
+func (ar *apptRepo) RescheduleAppointment(appointmentID int, doctorID int, startTime time.Time, actorID int, actorType string, expiresAt time.Time) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to reschedule appointment", err)
+	}
+	defer tx.Rollback()
+
+	if appErr := rescheduleAppointment(tx, appointmentID, doctorID, startTime, actorID, actorType, expiresAt); appErr != nil {
+		return appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing rescheduled appointment", err)
+	}
+
+	return nil
+}
+
+// rescheduleAppointment moves the appointment within the transaction and
+// records its previous slot in its history. Unless expiresAt is zero, the
+// appointment becomes a booking request expiring then, otherwise a request is
+// confirmed as it needs no more approval.
+func rescheduleAppointment(tx *sql.Tx, appointmentID int, doctorID int, startTime time.Time, actorID int, actorType string, expiresAt time.Time) errors.AppointmentErr {
+	var oldDoctorID int
+	var oldStartTime time.Time
+
+	query := "SELECT doctor_id, start_time FROM appointments WHERE id=? AND is_active=1 AND status IN (?, ?);"
+
+	if err := tx.QueryRow(query, appointmentID, StatusRequested, StatusConfirmed).Scan(&oldDoctorID, &oldStartTime); err != nil {
+		return errors.NewGeneralError(fmt.Sprintf("appointment id %d can no longer be rescheduled", appointmentID), nil)
+	}
+
+	seat, appErr := freeSeat(tx, doctorID, startTime)
+	if appErr != nil {
+		return appErr
+	}
+
+	query = "UPDATE appointments SET doctor_id=?, start_time=?, seat=? WHERE id=?;"
+
+	if _, err := tx.Exec(query, doctorID, startTime, seat, appointmentID); err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Slot already taken", nil)
+		}
+
+		return errors.NewInternalServerError("error occured when executing statement to reschedule appointment", err)
+	}
+
+	if !expiresAt.IsZero() {
+		query = "UPDATE appointments SET status=?, confirmed_at=NULL, request_expires_at=? WHERE id=?;"
+
+		if _, err := tx.Exec(query, StatusRequested, expiresAt.UTC(), appointmentID); err != nil {
+			return errors.NewInternalServerError("error occured when executing statement to request rescheduled appointment", err)
+		}
+	} else {
+		query = "UPDATE appointments SET status=?, confirmed_at=?, request_expires_at=NULL WHERE id=? AND status=?;"
+
+		if _, err := tx.Exec(query, StatusConfirmed, time.Now().UTC(), appointmentID, StatusRequested); err != nil {
+			return errors.NewInternalServerError("error occured when executing statement to confirm rescheduled appointment", err)
+		}
+	}
+
+	query = "INSERT INTO appointment_history(appointment_id, event, doctor_id, start_time, actor_id, actor_type) VALUES (?, 'rescheduled', ?, ?, ?, ?);"
+
+	if _, err := tx.Exec(query, appointmentID, oldDoctorID, oldStartTime, actorID, actorType); err != nil {
+		return errors.NewInternalServerError("error occured when recording appointment history", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRepo_BookSlotConcurrent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		capacity int
+		patients int
+	}{
+		{
+			name:     "Single Patient Slot",
+			capacity: 1,
+			patients: 20,
+		},
+		{
+			name:     "Group Slot",
+			capacity: 8,
+			patients: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "appointments.db")+dsnOptions)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening database", err)
+			}
+			defer db.Close()
+
+			if err := AutoMigrate(db); err != nil {
+				t.Fatalf("an error '%s' was not expected when migrating database", err)
+			}
+
+			s := NewAppointmentRepository(db)
+
+			doctorID, appErr := s.CreateDoctorAccount(Doctor{Name: "Doctor1"})
+			if appErr != nil {
+				t.Fatalf("an error '%s' was not expected when creating doctor", appErr.GetMessage())
+			}
+
+			startTime := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+
+			if _, appErr := s.AddSchedule(doctorID, startTime, startTime.Add(time.Hour), tt.capacity, ""); appErr != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", appErr.GetMessage())
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			booked, conflicts := 0, 0
+
+			for i := 1; i <= tt.patients; i++ {
+				wg.Add(1)
+
+				go func(patientID int) {
+					defer wg.Done()
+
+					_, appErr := s.BookSlot(doctorID, patientID, patientID, startTime, "")
+
+					mu.Lock()
+					defer mu.Unlock()
+
+					switch {
+					case appErr == nil:
+						booked++
+					case appErr.GetStatus() == http.StatusConflict:
+						conflicts++
+					default:
+						t.Errorf("BookSlot() unexpected error = %s: %s", appErr.GetMessage(), appErr.GetError())
+					}
+				}(i)
+			}
+
+			wg.Wait()
+
+			if booked != tt.capacity || conflicts != tt.patients-tt.capacity {
+				t.Errorf("BookSlot() booked = %d, conflicts = %d, want %d, %d", booked, conflicts, tt.capacity, tt.patients-tt.capacity)
+			}
+
+			var count int
+			if err := db.QueryRow("SELECT COUNT(id) FROM appointments WHERE is_active=1;").Scan(&count); err != nil {
+				t.Fatalf("an error '%s' was not expected when counting appointments", err)
+			}
+
+			if count != tt.capacity {
+				t.Errorf("active appointments = %d, want %d", count, tt.capacity)
+			}
+		})
+	}
+}
+
+func TestRepo_CheckSlotAvailableGroupSlot(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 3
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "appointments.db")+dsnOptions)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening database", err)
+	}
+	defer db.Close()
+
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("an error '%s' was not expected when migrating database", err)
+	}
+
+	s := NewAppointmentRepository(db)
+
+	doctorID, appErr := s.CreateDoctorAccount(Doctor{Name: "Doctor1"})
+	if appErr != nil {
+		t.Fatalf("an error '%s' was not expected when creating doctor", appErr.GetMessage())
+	}
+
+	startTime := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+
+	if _, appErr := s.AddSchedule(doctorID, startTime, startTime.Add(time.Hour), capacity, ""); appErr != nil {
+		t.Fatalf("an error '%s' was not expected when adding schedule", appErr.GetMessage())
+	}
+
+	// The slot stays available, and not booked, until every seat is taken
+	for patientID := 1; patientID <= capacity+1; patientID++ {
+		available, appErr := s.CheckSlotAvailable(doctorID, startTime)
+		if appErr != nil {
+			t.Fatalf("CheckSlotAvailable() error = %s", appErr.GetMessage())
+		}
+
+		if want := patientID <= capacity; available != want {
+			t.Errorf("CheckSlotAvailable() with %d booked = %v, want %v", patientID-1, available, want)
+		}
+
+		slots, appErr := s.ListSchedule(doctorID, startTime, startTime.Add(SlotMinutes*time.Minute))
+		if appErr != nil {
+			t.Fatalf("an error '%s' was not expected when listing schedule", appErr.GetMessage())
+		}
+
+		if len(slots) != 1 || slots[0].Remaining != capacity-patientID+1 || slots[0].Booked == available {
+			t.Errorf("ListSchedule() with %d booked = %+v, want %d remaining, booked %v", patientID-1, slots, capacity-patientID+1, !available)
+		}
+
+		if !available {
+			break
+		}
+
+		if _, appErr := s.BookSlot(doctorID, patientID, patientID, startTime, ""); appErr != nil {
+			t.Fatalf("an error '%s' was not expected when booking", appErr.GetMessage())
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// CalendarFeed is the iCalendar feed of a Doctor or Patient, published at a
+// URL made secret by Secret.
+type CalendarFeed struct {
+	UserID    int       `json:"userid"`
+	UserType  string    `json:"usertype"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"createdat"`
+}
+
+// FeedBooking is an appointment of a calendar feed. Sequence counts the
+// changes to the appointment, for calendar apps to pick the latest version.
+type FeedBooking struct {
+	Booking
+	DoctorName  string
+	PatientName string
+	Sequence    int
+}
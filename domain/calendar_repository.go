@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+func (ar *apptRepo) GetCalendarFeed(userID int, userType string) (CalendarFeed, errors.AppointmentErr) {
+	feed := CalendarFeed{UserID: userID, UserType: userType}
+
+	query := "SELECT secret, created_at FROM calendar_feed WHERE user_id=? AND user_type=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return feed, errors.NewInternalServerError("error occured when preparing statement to fetch calendar feed", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.QueryRow(userID, userType).Scan(&feed.Secret, &feed.CreatedAt); err != nil {
+		return feed, errors.NewNotFoundError("calendar feed does not exist in database", err)
+	}
+
+	return feed, nil
+}
+
+func (ar *apptRepo) GetCalendarFeedBySecret(secret string) (CalendarFeed, errors.AppointmentErr) {
+	feed := CalendarFeed{Secret: secret}
+
+	query := "SELECT user_id, user_type, created_at FROM calendar_feed WHERE secret=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return feed, errors.NewInternalServerError("error occured when preparing statement to fetch calendar feed", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.QueryRow(secret).Scan(&feed.UserID, &feed.UserType, &feed.CreatedAt); err != nil {
+		return feed, errors.NewNotFoundError("calendar feed does not exist in database", err)
+	}
+
+	return feed, nil
+}
+
+// SaveCalendarFeed creates the calendar feed of the user, or replaces its
+// secret so the previous URL stops working.
+func (ar *apptRepo) SaveCalendarFeed(feed CalendarFeed) errors.AppointmentErr {
+	query := "INSERT INTO calendar_feed(user_id, user_type, secret, created_at) VALUES (?, ?, ?, ?) ON CONFLICT(user_id, user_type) DO UPDATE SET secret=excluded.secret, created_at=excluded.created_at;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to save calendar feed", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(feed.UserID, feed.UserType, feed.Secret, feed.CreatedAt.UTC()); err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to save calendar feed", err)
+	}
+
+	return nil
+}
+
+// GetFeedBookings returns the appointments of the Doctor, or of the Patient
+// account and its dependents, starting between from and to in order of start
+// time. Cancelled appointments are included for calendar apps to remove them.
+func (ar *apptRepo) GetFeedBookings(doctorID int, accountID int, from time.Time, to time.Time) ([]FeedBooking, errors.AppointmentErr) {
+	bookings := make([]FeedBooking, 0)
+
+	query := "SELECT " + bookingColumns + ", d.name, COALESCE(p.name, ''), (SELECT COUNT(h.id) FROM appointment_history h WHERE h.appointment_id=a.id) FROM appointments a JOIN doctor d ON d.id=a.doctor_id LEFT JOIN patient p ON p.id=a.patient_id WHERE a.start_time>=? AND a.start_time<?"
+	args := []interface{}{from.UTC(), to.UTC()}
+
+	if doctorID != 0 {
+		query += " AND a.doctor_id=?"
+		args = append(args, doctorID)
+	} else {
+		query += " AND (a.patient_id=? OR a.booked_by=? OR p.account_id=?)"
+		args = append(args, accountID, accountID, accountID)
+	}
+
+	query += " ORDER BY a.start_time, a.id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when preparing statement to fetch calendar appointments", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when executing statement to fetch calendar appointments", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking FeedBooking
+
+		if err := scanBooking(rows, &booking.Booking, &booking.DoctorName, &booking.PatientName, &booking.Sequence); err != nil {
+			return bookings, errors.NewInternalServerError("error occured when parsing calendar appointments", err)
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// GetSchedules returns the schedules of the Doctor overlapping from and to, in
+// order of start time.
+func (ar *apptRepo) GetSchedules(doctorID int, from time.Time, to time.Time) ([]Schedule, errors.AppointmentErr) {
+	schedules := make([]Schedule, 0)
+
+	query := "SELECT id, doctor_id, start_time, end_time, capacity, appointment_type FROM doctor_schedule WHERE doctor_id=? AND end_time>? AND start_time<? ORDER BY start_time;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return schedules, errors.NewInternalServerError("error occured when preparing statement to fetch Doctor schedules", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(doctorID, from.UTC(), to.UTC())
+	if err != nil {
+		return schedules, errors.NewInternalServerError("error occured when executing statement to fetch Doctor schedules", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule Schedule
+
+		if err := rows.Scan(&schedule.ID, &schedule.DoctorID, &schedule.StartTime, &schedule.EndTime, &schedule.Capacity, &schedule.AppointmentType); err != nil {
+			return schedules, errors.NewInternalServerError("error occured when parsing Doctor schedules", err)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,22 @@
+package domain
+
+// CancellationReport sums up the cancelled appointments of a Doctor starting
+// between From and To, by who cancelled them. Late lists the late
+// cancellations.
+type CancellationReport struct {
+	DoctorID      int                 `json:"doctorid"`
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	Total         int                 `json:"total"`
+	LateTotal     int                 `json:"latetotal"`
+	Cancellations []CancellationCount `json:"cancellations"`
+	Late          []Booking           `json:"late"`
+}
+
+// CancellationCount counts the cancellations made by one type of user, e.g.
+// "patient", or "system" for expired requests.
+type CancellationCount struct {
+	CancelledByType string `json:"cancelledbytype"`
+	Count           int    `json:"count"`
+	Late            int    `json:"late"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// GetCancellations returns the cancellations of the Doctor's appointments
+// starting from from until to, counted by who cancelled them, and the late
+// ones in order of start time.
+func (ar *apptRepo) GetCancellations(doctorID int, from time.Time, to time.Time) ([]CancellationCount, []Booking, errors.AppointmentErr) {
+	counts := make([]CancellationCount, 0)
+	late := make([]Booking, 0)
+
+	query := "SELECT COALESCE(cancelled_by_type, ''), COUNT(id), SUM(late_cancellation) FROM appointments WHERE doctor_id=? AND status=? AND start_time>=? AND start_time<? GROUP BY COALESCE(cancelled_by_type, '') ORDER BY COALESCE(cancelled_by_type, '');"
+
+	rows, err := ar.db.Query(query, doctorID, StatusCancelled, from.UTC(), to.UTC())
+	if err != nil {
+		return counts, late, errors.NewInternalServerError("error occured when executing statement to count cancellations", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count CancellationCount
+
+		if err := rows.Scan(&count.CancelledByType, &count.Count, &count.Late); err != nil {
+			return counts, late, errors.NewInternalServerError("error occured when parsing cancellation counts", err)
+		}
+
+		counts = append(counts, count)
+	}
+
+	rows.Close()
+
+	query = "SELECT " + bookingColumns + " FROM appointments a LEFT JOIN patient p ON p.id=a.patient_id WHERE a.doctor_id=? AND a.status=? AND a.late_cancellation=1 AND a.start_time>=? AND a.start_time<? ORDER BY a.start_time, a.id;"
+
+	rows, err = ar.db.Query(query, doctorID, StatusCancelled, from.UTC(), to.UTC())
+	if err != nil {
+		return counts, late, errors.NewInternalServerError("error occured when executing statement to fetch late cancellations", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking Booking
+
+		if err := scanBooking(rows, &booking); err != nil {
+			return counts, late, errors.NewInternalServerError("error occured when parsing late cancellations", err)
+		}
+
+		late = append(late, booking)
+	}
+
+	return counts, late, nil
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"appointment/errors"
+	"fmt"
+)
+
+// CreateDelegateAccount creates an account acting on behalf of the Doctor,
+// e.g. for their assistant.
+func (ar *apptRepo) CreateDelegateAccount(doctorID int, name string) (int, errors.AppointmentErr) {
+	var id int
+	query := "INSERT INTO delegate(doctor_id, name) VALUES (?, ?);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to create new delegate account", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(doctorID, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return id, errors.NewGeneralError("account already exists", nil)
+		}
+
+		return id, errors.NewInternalServerError("error occured when executing statement to create new delegate account", err)
+	}
+
+	newId, err := result.LastInsertId()
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when getting delegate ID", err)
+	}
+
+	id = int(newId)
+
+	return id, nil
+}
+
+// GetDelegateDoctor returns the ID of the Doctor the delegate acts for.
+// AdminExists checks the Admin account adminID exists.
+func (ar *apptRepo) AdminExists(adminID int) (bool, errors.AppointmentErr) {
+	var count int
+
+	query := "SELECT COUNT(id) FROM admin WHERE id=?;"
+
+	if err := ar.db.QueryRow(query, adminID).Scan(&count); err != nil {
+		return false, errors.NewInternalServerError("error occured when executing statement to check for admin account", err)
+	}
+
+	return count != 0, nil
+}
+
+func (ar *apptRepo) GetDelegateDoctor(delegateID int) (int, errors.AppointmentErr) {
+	var doctorID int
+	query := "SELECT doctor_id FROM delegate WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return doctorID, errors.NewInternalServerError("error occured when preparing statement to fetch delegate", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(delegateID)
+	if err = result.Scan(&doctorID); err != nil {
+		return doctorID, errors.NewNotFoundError(fmt.Sprintf("Delegate %d not found in database", delegateID), err)
+	}
+
+	return doctorID, nil
+}
@@ -1,6 +1,8 @@
 package domain
 
 type Doctor struct {
-	ID   int    `json:"userid"`
-	Name string `json:"name"`
+	ID        int    `json:"userid"`
+	Name      string `json:"name"`
+	Region    string `json:"region"`
+	Specialty string `json:"specialty"`
 }
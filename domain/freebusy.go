@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Interval is the time from Start up to End.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// FreeBusy is the time the Doctor is busy with appointments, as intervals in
+// order of start time that neither overlap nor touch.
+type FreeBusy struct {
+	DoctorID   int        `json:"doctorid"`
+	DoctorName string     `json:"doctorname"`
+	Busy       []Interval `json:"busy"`
+}
+
+// addBusy adds the interval to the busy time, merging it into the last one if
+// they overlap or touch. Intervals are added in order of start time.
+func (fb *FreeBusy) addBusy(interval Interval) {
+	if last := len(fb.Busy) - 1; last >= 0 && !interval.Start.After(fb.Busy[last].End) {
+		if interval.End.After(fb.Busy[last].End) {
+			fb.Busy[last].End = interval.End
+		}
+
+		return
+	}
+
+	fb.Busy = append(fb.Busy, interval)
+}
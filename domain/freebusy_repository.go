@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// GetFreeBusy returns the time each of the Doctors is busy with appointments
+// between from and to, in order of Doctor ID. The appointments of all Doctors
+// are fetched in one query, Doctors that do not exist are left out.
+func (ar *apptRepo) GetFreeBusy(doctorIDs []int, from time.Time, to time.Time) ([]FreeBusy, errors.AppointmentErr) {
+	freeBusy := make([]FreeBusy, 0)
+
+	from, to = from.UTC(), to.UTC()
+
+	// Appointments last one slot, so those starting a slot before from may
+	// still be going on
+	query := "SELECT d.id, d.name, a.start_time, COALESCE(a.duration_minutes, 0) FROM doctor d LEFT JOIN appointments a ON a.doctor_id=d.id AND a.is_active=1 AND a.start_time>? AND a.start_time<? WHERE d.id IN (" + placeholders(len(doctorIDs)) + ") ORDER BY d.id, a.start_time;"
+	args := []interface{}{from.Add(-SlotMinutes * time.Minute), to}
+
+	for _, id := range doctorIDs {
+		args = append(args, id)
+	}
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return freeBusy, errors.NewInternalServerError("error occured when preparing statement to fetch busy time", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return freeBusy, errors.NewInternalServerError("error occured when executing statement to fetch busy time", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doctorID, duration int
+		var name string
+		var startTime *time.Time
+
+		if err := rows.Scan(&doctorID, &name, &startTime, &duration); err != nil {
+			return freeBusy, errors.NewInternalServerError("error occured when parsing busy time", err)
+		}
+
+		if last := len(freeBusy) - 1; last < 0 || freeBusy[last].DoctorID != doctorID {
+			freeBusy = append(freeBusy, FreeBusy{DoctorID: doctorID, DoctorName: name, Busy: make([]Interval, 0)})
+		}
+
+		// Doctors without appointments
+		if startTime == nil {
+			continue
+		}
+
+		busy := Interval{Start: startTime.UTC(), End: startTime.UTC().Add(time.Duration(duration) * time.Minute)}
+
+		if !busy.End.After(from) {
+			continue
+		}
+
+		if busy.Start.Before(from) {
+			busy.Start = from
+		}
+
+		if busy.End.After(to) {
+			busy.End = to
+		}
+
+		freeBusy[len(freeBusy)-1].addBusy(busy)
+	}
+
+	return freeBusy, nil
+}
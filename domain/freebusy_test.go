@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddBusy(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 7, 18, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Interval
+	}{
+		{
+			name:      "Apart",
+			intervals: []Interval{{at(9, 0), at(9, 15)}, {at(10, 0), at(10, 15)}},
+			want:      []Interval{{at(9, 0), at(9, 15)}, {at(10, 0), at(10, 15)}},
+		},
+		{
+			name:      "Back To Back",
+			intervals: []Interval{{at(9, 0), at(9, 15)}, {at(9, 15), at(9, 30)}, {at(9, 30), at(9, 45)}},
+			want:      []Interval{{at(9, 0), at(9, 45)}},
+		},
+		{
+			// Group sessions have several appointments in the same slot
+			name:      "Same Slot",
+			intervals: []Interval{{at(9, 0), at(9, 15)}, {at(9, 0), at(9, 15)}, {at(9, 30), at(9, 45)}},
+			want:      []Interval{{at(9, 0), at(9, 15)}, {at(9, 30), at(9, 45)}},
+		},
+		{
+			name:      "Contained",
+			intervals: []Interval{{at(9, 0), at(10, 0)}, {at(9, 15), at(9, 30)}},
+			want:      []Interval{{at(9, 0), at(10, 0)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fb FreeBusy
+
+			for _, interval := range tt.intervals {
+				fb.addBusy(interval)
+			}
+
+			if !reflect.DeepEqual(fb.Busy, tt.want) {
+				t.Errorf("addBusy() = %v, want %v", fb.Busy, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	HoldHeld      = "held"
+	HoldConfirmed = "confirmed"
+	HoldReleased  = "released"
+	HoldExpired   = "expired"
+)
+
+// Hold reserves a seat of a slot for a Patient until ExpiresAt, so it can be
+// confirmed into an appointment.
+type Hold struct {
+	ID            int       `json:"holdid"`
+	DoctorID      int       `json:"doctorid"`
+	PatientID     int       `json:"patientid"`
+	StartTime     time.Time `json:"starttime"`
+	ExpiresAt     time.Time `json:"expiresat"`
+	Status        string    `json:"status"`
+	AppointmentID int       `json:"appointmentid,omitempty"`
+	WaitlistID    int       `json:"waitlistid,omitempty"`
+}
@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"appointment/errors"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// HoldSlot reserves a free seat of the slot for the Patient until expiresAt,
+// for the waitlist entry waitlistID if any. A full slot results in a Conflict
+// error.
+func (ar *apptRepo) HoldSlot(doctorID int, patientID int, startTime time.Time, expiresAt time.Time, waitlistID int) (int, errors.AppointmentErr) {
+	var holdID int
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return holdID, errors.NewInternalServerError("error occured when starting transaction for holding slot", err)
+	}
+	defer tx.Rollback()
+
+	seat, appErr := freeSeat(tx, doctorID, startTime)
+	if appErr != nil {
+		return holdID, appErr
+	}
+
+	query := "INSERT INTO slot_hold(doctor_id, patient_id, start_time, seat, expires_at, waitlist_id) VALUES (?, ?, ?, ?, ?, NULLIF(?, 0));"
+
+	result, err := tx.Exec(query, doctorID, patientID, startTime, seat, expiresAt.UTC(), waitlistID)
+	if err != nil {
+		return holdID, errors.NewInternalServerError("error occured when executing statement for holding slot", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return holdID, errors.NewInternalServerError("error occured when getting hold ID", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return holdID, errors.NewInternalServerError("error occured when committing held slot", err)
+	}
+
+	holdID = int(id)
+
+	return holdID, nil
+}
+
+// GetHold returns the hold, reporting held ones past their expiry as expired.
+func (ar *apptRepo) GetHold(holdID int) (Hold, errors.AppointmentErr) {
+	var hold Hold
+
+	query := "SELECT id, doctor_id, patient_id, start_time, expires_at, status, COALESCE(appointment_id, 0), COALESCE(waitlist_id, 0) FROM slot_hold WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return hold, errors.NewInternalServerError("error occured when preparing statement to fetch hold", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(holdID)
+	if err = result.Scan(&hold.ID, &hold.DoctorID, &hold.PatientID, &hold.StartTime, &hold.ExpiresAt, &hold.Status, &hold.AppointmentID, &hold.WaitlistID); err != nil {
+		if err == sql.ErrNoRows {
+			return hold, errors.NewNotFoundError(fmt.Sprintf("hold id %d does not exist in database", holdID), err)
+		}
+
+		return hold, errors.NewInternalServerError("error occured when fetching hold", err)
+	}
+
+	if hold.Status == HoldHeld && !hold.ExpiresAt.After(time.Now()) {
+		hold.Status = HoldExpired
+	}
+
+	return hold, nil
+}
+
+// ConfirmHold books the held seat for the Patient who holds it, within a
+// transaction, and returns the appointment ID. A non-zero expiresAt books it
+// as a request pending the approval of the Doctor until then.
+func (ar *apptRepo) ConfirmHold(holdID int, patientID int, expiresAt time.Time) (int, errors.AppointmentErr) {
+	var appointmentID int
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return appointmentID, errors.NewInternalServerError("error occured when starting transaction to confirm hold", err)
+	}
+	defer tx.Rollback()
+
+	hold, appErr := lockHold(tx, holdID, patientID)
+	if appErr != nil {
+		return appointmentID, appErr
+	}
+
+	var confirmedAt, requestExpiresAt interface{} = time.Now().UTC(), nil
+
+	status := StatusConfirmed
+	if !expiresAt.IsZero() {
+		status, confirmedAt, requestExpiresAt = StatusRequested, nil, expiresAt.UTC()
+	}
+
+	query := "INSERT INTO appointments(doctor_id, patient_id, booked_by, start_time, is_active, seat, duration_minutes, status, confirmed_at, request_expires_at) VALUES (?, ?, ?, ?, 1, ?, ?, ?, ?, ?);"
+
+	result, err := tx.Exec(query, hold.DoctorID, hold.PatientID, hold.PatientID, hold.StartTime, hold.seat, SlotMinutes, status, confirmedAt, requestExpiresAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return appointmentID, errors.NewConflictError("Slot already taken", nil)
+		}
+
+		return appointmentID, errors.NewInternalServerError("error occured when executing statement for booking held slot", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return appointmentID, errors.NewInternalServerError("error occured when getting appointment ID", err)
+	}
+
+	query = "UPDATE slot_hold SET status='confirmed', appointment_id=? WHERE id=?;"
+
+	if _, err := tx.Exec(query, id, holdID); err != nil {
+		return appointmentID, errors.NewInternalServerError("error occured when executing statement to confirm hold", err)
+	}
+
+	if appErr := closeWaitlistOffer(tx, hold.WaitlistID, WaitlistBooked, id); appErr != nil {
+		return appointmentID, appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return appointmentID, errors.NewInternalServerError("error occured when committing confirmed hold", err)
+	}
+
+	appointmentID = int(id)
+
+	return appointmentID, nil
+}
+
+func (ar *apptRepo) ReleaseHold(holdID int, patientID int) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to release hold", err)
+	}
+	defer tx.Rollback()
+
+	hold, appErr := lockHold(tx, holdID, patientID)
+	if appErr != nil {
+		return appErr
+	}
+
+	query := "UPDATE slot_hold SET status='released' WHERE id=?;"
+
+	if _, err := tx.Exec(query, holdID); err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to release hold", err)
+	}
+
+	// Releasing an offered slot declines it
+	if appErr := closeWaitlistOffer(tx, hold.WaitlistID, WaitlistRemoved, 0); appErr != nil {
+		return appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing released hold", err)
+	}
+
+	return nil
+}
+
+// ExpireHolds marks the holds past their expiry at now as expired, together
+// with the waitlist offers they were for, and returns them.
+func (ar *apptRepo) ExpireHolds(now time.Time) ([]Hold, errors.AppointmentErr) {
+	expired := make([]Hold, 0)
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return expired, errors.NewInternalServerError("error occured when starting transaction to expire holds", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, doctor_id, patient_id, start_time, expires_at, status, COALESCE(appointment_id, 0), COALESCE(waitlist_id, 0) FROM slot_hold WHERE status=? AND expires_at<=?;"
+
+	rows, err := tx.Query(query, HoldHeld, now.UTC())
+	if err != nil {
+		return expired, errors.NewInternalServerError("error occured when executing statement to fetch expired holds", err)
+	}
+
+	for rows.Next() {
+		var hold Hold
+
+		if err := rows.Scan(&hold.ID, &hold.DoctorID, &hold.PatientID, &hold.StartTime, &hold.ExpiresAt, &hold.Status, &hold.AppointmentID, &hold.WaitlistID); err != nil {
+			rows.Close()
+
+			return expired, errors.NewInternalServerError("error occured when parsing expired holds", err)
+		}
+
+		hold.Status = HoldExpired
+		expired = append(expired, hold)
+	}
+	rows.Close()
+
+	for _, hold := range expired {
+		query = "UPDATE slot_hold SET status=? WHERE id=?;"
+
+		if _, err := tx.Exec(query, HoldExpired, hold.ID); err != nil {
+			return expired, errors.NewInternalServerError("error occured when executing statement to expire hold", err)
+		}
+
+		if appErr := closeWaitlistOffer(tx, hold.WaitlistID, WaitlistExpired, 0); appErr != nil {
+			return expired, appErr
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return expired, errors.NewInternalServerError("error occured when committing expired holds", err)
+	}
+
+	return expired, nil
+}
+
+// closeWaitlistOffer moves the waitlist entry offered a hold, if any, to
+// status within the transaction.
+func closeWaitlistOffer(tx *sql.Tx, waitlistID int, status string, appointmentID int64) errors.AppointmentErr {
+	if waitlistID == 0 {
+		return nil
+	}
+
+	query := "UPDATE waitlist SET status=?, appointment_id=NULLIF(?, 0) WHERE id=? AND status=?;"
+
+	if _, err := tx.Exec(query, status, appointmentID, waitlistID, WaitlistOffered); err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to update waitlist offer", err)
+	}
+
+	return nil
+}
+
+type heldSeat struct {
+	Hold
+	seat int
+}
+
+// lockHold fetches the hold within the transaction, checking that it belongs
+// to the Patient and is still held.
+func lockHold(tx *sql.Tx, holdID int, patientID int) (heldSeat, errors.AppointmentErr) {
+	var hold heldSeat
+
+	query := "SELECT id, doctor_id, patient_id, start_time, expires_at, status, COALESCE(waitlist_id, 0), seat FROM slot_hold WHERE id=?;"
+
+	err := tx.QueryRow(query, holdID).Scan(&hold.ID, &hold.DoctorID, &hold.PatientID, &hold.StartTime, &hold.ExpiresAt, &hold.Status, &hold.WaitlistID, &hold.seat)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return hold, errors.NewNotFoundError(fmt.Sprintf("hold id %d does not exist in database", holdID), err)
+		}
+
+		return hold, errors.NewInternalServerError("error occured when fetching hold", err)
+	}
+
+	if hold.PatientID != patientID {
+		return hold, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	if hold.Status != HoldHeld {
+		return hold, errors.NewGeneralError(fmt.Sprintf("hold id %d is already %s", holdID, hold.Status), nil)
+	}
+
+	if !hold.ExpiresAt.After(time.Now()) {
+		return hold, errors.NewGeneralError(fmt.Sprintf("hold id %d has expired", holdID), nil)
+	}
+
+	return hold, nil
+}
@@ -0,0 +1,10 @@
+package domain
+
+// DateFormat is the layout holiday dates are stored and exchanged in.
+const DateFormat = "2006-01-02"
+
+type Holiday struct {
+	Region string `json:"region"`
+	Date   string `json:"date"`
+	Name   string `json:"name"`
+}
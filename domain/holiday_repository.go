@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+func (ar *apptRepo) CreateAdminAccount(name string) (int, errors.AppointmentErr) {
+	var id int
+	query := "SELECT COUNT(id) FROM admin WHERE name=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to check for existing admin account", err)
+	}
+	defer stmt.Close()
+
+	var count int
+
+	result := stmt.QueryRow(name)
+	if err = result.Scan(&count); err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to check for existing admin account", err)
+	}
+
+	if count != 0 {
+		return id, errors.NewGeneralError("account already exists", nil)
+	}
+
+	query = "INSERT INTO admin(name) VALUES (?);"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to create new admin account", err)
+	}
+	defer stmt.Close()
+
+	result2, err := stmt.Exec(name)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to create new admin account", err)
+	}
+
+	newId, err := result2.LastInsertId()
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when getting admin ID", err)
+	}
+
+	id = int(newId)
+
+	return id, nil
+}
+
+// AddHolidays stores the given holidays, replacing the name of any holiday
+// already present for the same region and date. It returns the number of
+// holidays stored.
+func (ar *apptRepo) AddHolidays(holidays []Holiday) (int, errors.AppointmentErr) {
+	var count int
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return count, errors.NewInternalServerError("error occured when starting transaction to store holidays", err)
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO holiday(region, holiday_date, name) VALUES (?, ?, ?) ON CONFLICT(region, holiday_date) DO UPDATE SET name=excluded.name;"
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return count, errors.NewInternalServerError("error occured when preparing statement to store holidays", err)
+	}
+	defer stmt.Close()
+
+	for _, holiday := range holidays {
+		_, err = stmt.Exec(holiday.Region, holiday.Date, holiday.Name)
+		if err != nil {
+			return count, errors.NewInternalServerError("error occured when executing statement to store holidays", err)
+		}
+
+		count++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.NewInternalServerError("error occured when committing holidays", err)
+	}
+
+	return count, nil
+}
+
+func (ar *apptRepo) OptInHoliday(doctorID int, date string) errors.AppointmentErr {
+	query := "INSERT OR IGNORE INTO doctor_holiday_optin(doctor_id, holiday_date) VALUES (?, ?);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to opt in to holiday", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(doctorID, date)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to opt in to holiday", err)
+	}
+
+	return nil
+}
+
+// GetHolidays returns the holidays between from and to (inclusive) that block
+// the availability of the Doctor, keyed by date. Holidays the Doctor opted in
+// to work on are left out.
+func (ar *apptRepo) GetHolidays(doctorID int, from time.Time, to time.Time) (map[string]string, errors.AppointmentErr) {
+	holidays := make(map[string]string)
+
+	query := "SELECT h.holiday_date, COALESCE(h.name, '') FROM holiday h JOIN doctor d ON d.region=h.region WHERE d.id=? AND h.holiday_date>=? AND h.holiday_date<=? AND NOT EXISTS (SELECT 1 FROM doctor_holiday_optin o WHERE o.doctor_id=d.id AND o.holiday_date=h.holiday_date);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return holidays, errors.NewInternalServerError("error occured when preparing statement to fetch holidays", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(doctorID, from.Format(DateFormat), to.Format(DateFormat))
+	if err != nil {
+		return holidays, errors.NewInternalServerError("error occured when executing statement to fetch holidays", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var date, name string
+
+		if err := rows.Scan(&date, &name); err != nil {
+			return holidays, errors.NewInternalServerError("error occured when parsing holidays", err)
+		}
+
+		holidays[date] = name
+	}
+
+	return holidays, nil
+}
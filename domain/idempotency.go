@@ -0,0 +1,14 @@
+package domain
+
+// IdempotencyRecord is the response to a request sent with an Idempotency-Key,
+// replayed when the request is repeated. A zero StatusCode means the original
+// request is still being processed. Location is the Location header of the
+// response, if any.
+type IdempotencyRecord struct {
+	Key         string
+	Path        string
+	RequestHash string
+	StatusCode  int
+	Location    string
+	Response    []byte
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// ReserveIdempotencyKey claims the key of the record for its path, dropping
+// keys created before expiry. If the key is already in use it returns the
+// stored record and false.
+func (ar *apptRepo) ReserveIdempotencyKey(record IdempotencyRecord, expiry time.Time) (IdempotencyRecord, bool, errors.AppointmentErr) {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return record, false, errors.NewInternalServerError("error occured when starting transaction to reserve idempotency key", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM idempotency_key WHERE created_at<?;"
+
+	if _, err := tx.Exec(query, expiry.UTC()); err != nil {
+		return record, false, errors.NewInternalServerError("error occured when executing statement to drop expired idempotency keys", err)
+	}
+
+	query = "INSERT INTO idempotency_key(idempotency_key, path, request_hash, created_at) VALUES (?, ?, ?, ?) ON CONFLICT(idempotency_key, path) DO NOTHING;"
+
+	result, err := tx.Exec(query, record.Key, record.Path, record.RequestHash, time.Now().UTC())
+	if err != nil {
+		return record, false, errors.NewInternalServerError("error occured when executing statement to reserve idempotency key", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return record, false, errors.NewInternalServerError("error occured when getting reserved idempotency key", err)
+	}
+
+	reserved := rows == 1
+
+	if !reserved {
+		query = "SELECT request_hash, status_code, location, COALESCE(response, '') FROM idempotency_key WHERE idempotency_key=? AND path=?;"
+
+		if err := tx.QueryRow(query, record.Key, record.Path).Scan(&record.RequestHash, &record.StatusCode, &record.Location, &record.Response); err != nil {
+			return record, false, errors.NewInternalServerError("error occured when fetching idempotency key", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return record, false, errors.NewInternalServerError("error occured when committing idempotency key", err)
+	}
+
+	return record, reserved, nil
+}
+
+// SaveIdempotentResponse stores the response to the request holding the key.
+func (ar *apptRepo) SaveIdempotentResponse(record IdempotencyRecord) errors.AppointmentErr {
+	query := "UPDATE idempotency_key SET status_code=?, location=?, response=? WHERE idempotency_key=? AND path=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to save idempotent response", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(record.StatusCode, record.Location, record.Response, record.Key, record.Path)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to save idempotent response", err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey drops the key so the request can be retried.
+func (ar *apptRepo) ReleaseIdempotencyKey(key string, path string) errors.AppointmentErr {
+	query := "DELETE FROM idempotency_key WHERE idempotency_key=? AND path=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to release idempotency key", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(key, path)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to release idempotency key", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"appointment/database"
+	"database/sql"
+	"fmt"
+)
+
+// column is a column added to a table after it was first released.
+type column struct {
+	table      string
+	name       string
+	definition string
+}
+
+// addedColumns are the columns added to tables that existed before the schema
+// was versioned. Databases created in between may have any of them already.
+var addedColumns = []column{
+	{"doctor", "region", "VARCHAR(100) NOT NULL DEFAULT ''"},
+	{"doctor", "specialty", "VARCHAR(100) NOT NULL DEFAULT ''"},
+	{"doctor_schedule", "capacity", "INT NOT NULL DEFAULT 1"},
+	{"doctor_schedule", "appointment_type", "VARCHAR(100) NOT NULL DEFAULT ''"},
+	{"patient", "account_id", "INT NULL"},
+	{"patient", "contact", "VARCHAR(255) NOT NULL DEFAULT ''"},
+	{"appointments", "seat", "INT NOT NULL DEFAULT 1"},
+	{"appointments", "duration_minutes", "INT NOT NULL DEFAULT 15"},
+	{"appointments", "booked_by", "INT NULL"},
+	{"appointments", "reason", "VARCHAR(500) NOT NULL DEFAULT ''"},
+	{"appointments", "status", "VARCHAR(20) NOT NULL DEFAULT 'confirmed'"},
+	{"appointments", "confirmed_at", "TIMESTAMP NULL"},
+	{"appointments", "checked_in_at", "TIMESTAMP NULL"},
+	{"appointments", "started_at", "TIMESTAMP NULL"},
+	{"appointments", "completed_at", "TIMESTAMP NULL"},
+	{"appointments", "no_show_at", "TIMESTAMP NULL"},
+	{"appointments", "cancellation_reason", "VARCHAR(500) NOT NULL DEFAULT ''"},
+	{"appointments", "cancelled_by", "INT NULL"},
+	{"appointments", "cancelled_by_type", "VARCHAR(20) NULL"},
+	{"appointments", "late_cancellation", "INT NOT NULL DEFAULT 0"},
+	{"appointments", "deposit_required", "INT NOT NULL DEFAULT 0"},
+	{"appointments", "series_id", "INT NULL"},
+	{"appointments", "initiated_by", "INT NULL"},
+	{"appointments", "initiated_by_type", "VARCHAR(20) NULL"},
+	{"appointments", "request_expires_at", "TIMESTAMP NULL"},
+	{"doctor_settings", "cancellation_cutoff_minutes", "INT NOT NULL DEFAULT 0"},
+	{"doctor_settings", "require_cancellation_reason", "INT NOT NULL DEFAULT 0"},
+	{"doctor_settings", "approval_required", "INT NOT NULL DEFAULT 0"},
+	{"doctor_settings", "request_expiry_hours", "INT NOT NULL DEFAULT 24"},
+	{"idempotency_key", "location", "VARCHAR(255) NOT NULL DEFAULT ''"},
+}
+
+// migrations upgrade a database from the schema version of their index to the
+// next one. Version 0 is any database from before the schema was versioned.
+// New tables and indexes need no migration, database.Schema creates them.
+var migrations = []func(*sql.Tx) error{
+	migrateUnversioned,
+	migrateHoldWaitlist,
+}
+
+// AutoMigrate brings the database up to date with database.Schema, tracking
+// the version in PRAGMA user_version.
+func AutoMigrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version;").Scan(&version); err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than %d", version, len(migrations))
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A new database gets the current schema as is
+	exists, err := tableExists(tx, "appointments")
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		for ; version < len(migrations); version++ {
+			if err := migrations[version](tx); err != nil {
+				return fmt.Errorf("migrating schema version %d: %w", version, err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(database.Schema); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version=%d;", len(migrations))); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateUnversioned adds the columns missing from the database and updates
+// what the new columns and indexes expect of existing rows.
+func migrateUnversioned(tx *sql.Tx) error {
+	for _, c := range addedColumns {
+		if err := addColumn(tx, c); err != nil {
+			return err
+		}
+	}
+
+	statements := []string{
+		// Appointments used to be cancelled by only deactivating them
+		"UPDATE appointments SET status='cancelled' WHERE is_active=0 AND status='confirmed';",
+		// Double bookings made before seats were enforced get a seat each
+		"UPDATE appointments SET seat=(SELECT COUNT(a.id) FROM appointments a WHERE a.doctor_id=appointments.doctor_id AND a.start_time=appointments.start_time AND a.is_active=1 AND a.id<=appointments.id) WHERE is_active=1 AND EXISTS (SELECT a.id FROM appointments a WHERE a.doctor_id=appointments.doctor_id AND a.start_time=appointments.start_time AND a.is_active=1 AND a.seat=appointments.seat AND a.id<>appointments.id);",
+		// Dependents share names, so only account holders' names are unique
+		"DROP INDEX IF EXISTS `patient_name_UNIQUE`;",
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateHoldWaitlist links the holds offered to the waitlist to their entry.
+func migrateHoldWaitlist(tx *sql.Tx) error {
+	return addColumn(tx, column{"slot_hold", "waitlist_id", "INT NULL"})
+}
+
+// addColumn adds the column to its table unless it is already there. Tables
+// that do not exist yet are left to database.Schema.
+func addColumn(tx *sql.Tx, c column) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(`%s`);", c.table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found, exists := false, false
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+
+		exists = true
+		if name == c.name {
+			found = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows.Close()
+
+	if !exists || found {
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", c.table, c.name, c.definition))
+
+	return err
+}
+
+func tableExists(tx *sql.Tx, table string) (bool, error) {
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(name) FROM sqlite_master WHERE type='table' AND name=?;", table).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// baselineSchema is the schema from before it was versioned.
+const baselineSchema = "CREATE TABLE IF NOT EXISTS `doctor` (`id` INTEGER PRIMARY KEY, `name` VARCHAR(100) NULL, `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP);" +
+	"CREATE UNIQUE INDEX IF NOT EXISTS `doctor_name_UNIQUE` ON `doctor` (`name` ASC);" +
+	"CREATE TABLE IF NOT EXISTS `doctor_schedule` (`id` INTEGER PRIMARY KEY, `doctor_id` INT NOT NULL, `start_time` TIMESTAMP NULL, `end_time` TIMESTAMP NULL, `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP);" +
+	"CREATE INDEX IF NOT EXISTS `schedule_doctor_id_INDEX` ON `doctor_schedule` (`doctor_id` ASC);" +
+	"CREATE TABLE IF NOT EXISTS `patient` (`id` INTEGER PRIMARY KEY, `name` VARCHAR(100) NULL, `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP);" +
+	"CREATE UNIQUE INDEX IF NOT EXISTS `patient_name_UNIQUE` ON `patient` (`name` ASC);" +
+	"CREATE TABLE IF NOT EXISTS `appointments` (`id` INTEGER PRIMARY KEY, `doctor_id` INT NOT NULL, `patient_id` INT NOT NULL, `start_time` TIMESTAMP NOT NULL, `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, `deleted_at` TIMESTAMP NULL, `is_active` INT);" +
+	"CREATE INDEX IF NOT EXISTS `doctor_id_active_st_INDEX` ON `appointments` (`doctor_id` ASC, `is_active` ASC, `start_time` ASC);"
+
+func TestAutoMigrate(t *testing.T) {
+	t.Parallel()
+
+	startTime := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		baseline bool
+	}{
+		{
+			name: "New Database",
+		},
+		{
+			name:     "Baseline Database",
+			baseline: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "appointments.db")+dsnOptions)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening database", err)
+			}
+			defer db.Close()
+
+			if tt.baseline {
+				if _, err := db.Exec(baselineSchema); err != nil {
+					t.Fatalf("an error '%s' was not expected when creating baseline schema", err)
+				}
+
+				// Two bookings of the same slot and a cancelled one
+				statements := []string{
+					"INSERT INTO doctor(id, name) VALUES (1, 'Doctor1');",
+					"INSERT INTO patient(id, name) VALUES (1, 'Patient1'), (2, 'Patient2');",
+					"INSERT INTO doctor_schedule(doctor_id, start_time, end_time) VALUES (1, ?, ?);",
+					"INSERT INTO appointments(id, doctor_id, patient_id, start_time, is_active) VALUES (1, 1, 1, ?, 1), (2, 1, 2, ?, 1);",
+					"INSERT INTO appointments(id, doctor_id, patient_id, start_time, deleted_at, is_active) VALUES (3, 1, 1, ?, CURRENT_TIMESTAMP, 0);",
+				}
+				args := [][]interface{}{
+					nil,
+					nil,
+					{startTime, startTime.Add(time.Hour)},
+					{startTime, startTime},
+					{startTime.Add(15 * time.Minute)},
+				}
+
+				for i, statement := range statements {
+					if _, err := db.Exec(statement, args[i]...); err != nil {
+						t.Fatalf("an error '%s' was not expected when inserting baseline data", err)
+					}
+				}
+			}
+
+			// Migrating again must change nothing
+			for i := 0; i < 2; i++ {
+				if err := AutoMigrate(db); err != nil {
+					t.Fatalf("an error '%s' was not expected when migrating database", err)
+				}
+			}
+
+			var version int
+			if err := db.QueryRow("PRAGMA user_version;").Scan(&version); err != nil {
+				t.Fatalf("an error '%s' was not expected when reading schema version", err)
+			}
+
+			if version != len(migrations) {
+				t.Errorf("schema version = %d, want %d", version, len(migrations))
+			}
+
+			s := NewAppointmentRepository(db)
+
+			doctorID, appErr := s.GetDoctorID("Doctor1")
+			if appErr != nil {
+				if tt.baseline {
+					t.Fatalf("an error '%s' was not expected when getting doctor", appErr.GetMessage())
+				}
+
+				if doctorID, appErr = s.CreateDoctorAccount(Doctor{Name: "Doctor1"}); appErr != nil {
+					t.Fatalf("an error '%s' was not expected when creating doctor", appErr.GetMessage())
+				}
+
+				if _, appErr = s.AddSchedule(doctorID, startTime, startTime.Add(time.Hour), 1, ""); appErr != nil {
+					t.Fatalf("an error '%s' was not expected when adding schedule", appErr.GetMessage())
+				}
+			}
+
+			if tt.baseline {
+				var seats int
+				if err := db.QueryRow("SELECT COUNT(DISTINCT seat) FROM appointments WHERE is_active=1;").Scan(&seats); err != nil {
+					t.Fatalf("an error '%s' was not expected when counting seats", err)
+				}
+
+				if seats != 2 {
+					t.Errorf("distinct seats = %d, want 2", seats)
+				}
+
+				booking, appErr := s.GetBooking(3)
+				if appErr != nil {
+					t.Fatalf("an error '%s' was not expected when getting booking", appErr.GetMessage())
+				}
+
+				if booking.Status != StatusCancelled {
+					t.Errorf("cancelled booking status = %q, want %q", booking.Status, StatusCancelled)
+				}
+			}
+
+			// Dependents may share a name with other patients
+			for accountID := 1; accountID <= 2; accountID++ {
+				if _, appErr := s.CreateDependent(accountID, "Patient1"); appErr != nil {
+					t.Fatalf("an error '%s' was not expected when creating dependent", appErr.GetMessage())
+				}
+			}
+
+			if _, appErr := s.BookSlot(doctorID, 1, 1, startTime.Add(30*time.Minute), "Check-up"); appErr != nil {
+				t.Errorf("an error '%s' was not expected when booking", appErr.GetMessage())
+			}
+		})
+	}
+}
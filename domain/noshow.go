@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// NoShowRecord sums up the missed appointments of a Patient. Count only
+// covers the current period, Total all time. A restricted Patient cannot
+// book before RestrictedUntil, or has to leave a deposit.
+type NoShowRecord struct {
+	PatientID       int        `json:"patientid"`
+	Count           int        `json:"noshows"`
+	Total           int        `json:"totalnoshows"`
+	LastNoShow      *time.Time `json:"lastnoshow,omitempty"`
+	RestrictedUntil *time.Time `json:"restricteduntil,omitempty"`
+	DepositRequired bool       `json:"depositrequired"`
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// MarkNoShows marks confirmed appointments that started before the given
+// time without the Patient checking in as no-shows. It returns the number of
+// appointments marked.
+func (ar *apptRepo) MarkNoShows(before time.Time) (int, errors.AppointmentErr) {
+	var count int
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return count, errors.NewInternalServerError("error occured when starting transaction to mark no-shows", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id FROM appointments WHERE status=? AND start_time<?;"
+
+	rows, err := tx.Query(query, StatusConfirmed, before.UTC())
+	if err != nil {
+		return count, errors.NewInternalServerError("error occured when executing statement to fetch missed appointments", err)
+	}
+
+	ids := make([]int, 0)
+
+	for rows.Next() {
+		var id int
+
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+
+			return count, errors.NewInternalServerError("error occured when parsing missed appointments", err)
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if appErr := updateStatus(tx, id, StatusConfirmed, StatusNoShow, 0, "system"); appErr != nil {
+			return count, appErr
+		}
+
+		count++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.NewInternalServerError("error occured when committing no-shows", err)
+	}
+
+	return count, nil
+}
+
+// GetNoShows counts the no-shows of the Patient, all time and since the given
+// time.
+func (ar *apptRepo) GetNoShows(patientID int, since time.Time) (NoShowRecord, errors.AppointmentErr) {
+	record := NoShowRecord{PatientID: patientID}
+
+	query := "SELECT no_show_at FROM appointments WHERE patient_id=? AND status=? ORDER BY no_show_at;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return record, errors.NewInternalServerError("error occured when preparing statement to fetch no-shows", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(patientID, StatusNoShow)
+	if err != nil {
+		return record, errors.NewInternalServerError("error occured when executing statement to fetch no-shows", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noShowAt time.Time
+
+		if err := rows.Scan(&noShowAt); err != nil {
+			return record, errors.NewInternalServerError("error occured when parsing no-shows", err)
+		}
+
+		record.Total++
+
+		if !noShowAt.Before(since) {
+			record.Count++
+		}
+
+		record.LastNoShow = &noShowAt
+	}
+
+	return record, nil
+}
+
+func (ar *apptRepo) RequireDeposit(appointmentID int) errors.AppointmentErr {
+	query := "UPDATE appointments SET deposit_required=1 WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to require deposit", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(appointmentID)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to require deposit", err)
+	}
+
+	return nil
+}
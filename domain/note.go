@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Note is a private visit note attached to an appointment by its Doctor or
+// one of their delegates. Notes are never shown to Patients.
+type Note struct {
+	ID            int       `json:"noteid"`
+	AppointmentID int       `json:"appointmentid"`
+	AuthorID      int       `json:"authorid"`
+	AuthorType    string    `json:"authortype"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"createdat"`
+}
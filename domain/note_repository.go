@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"appointment/errors"
+)
+
+func (ar *apptRepo) AddNote(note Note) (int, errors.AppointmentErr) {
+	var id int
+	query := "INSERT INTO appointment_note(appointment_id, author_id, author_type, note) VALUES (?, ?, ?, ?);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to add note", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(note.AppointmentID, note.AuthorID, note.AuthorType, note.Note)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to add note", err)
+	}
+
+	newId, err := result.LastInsertId()
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when getting note ID", err)
+	}
+
+	id = int(newId)
+
+	return id, nil
+}
+
+// GetNotes returns the notes of the appointment, oldest first.
+func (ar *apptRepo) GetNotes(appointmentID int) ([]Note, errors.AppointmentErr) {
+	notes := make([]Note, 0)
+
+	query := "SELECT id, appointment_id, author_id, author_type, note, created_at FROM appointment_note WHERE appointment_id=? ORDER BY id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return notes, errors.NewInternalServerError("error occured when preparing statement to fetch notes", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(appointmentID)
+	if err != nil {
+		return notes, errors.NewInternalServerError("error occured when executing statement to fetch notes", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note Note
+
+		if err := rows.Scan(&note.ID, &note.AppointmentID, &note.AuthorID, &note.AuthorType, &note.Note, &note.CreatedAt); err != nil {
+			return notes, errors.NewInternalServerError("error occured when parsing notes", err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
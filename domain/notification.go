@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type Notification struct {
+	ID        int       `json:"notificationid"`
+	Message   string    `json:"message"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"createdat"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"appointment/errors"
+)
+
+func (ar *apptRepo) AddNotification(patientID int, message string) errors.AppointmentErr {
+	query := "INSERT INTO notification(patient_id, message) VALUES (?, ?);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to add notification", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(patientID, message)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to add notification", err)
+	}
+
+	return nil
+}
+
+// GetNotifications returns the notifications of the Patient, latest first,
+// and marks them as read.
+func (ar *apptRepo) GetNotifications(patientID int) ([]Notification, errors.AppointmentErr) {
+	notifications := make([]Notification, 0)
+
+	query := "SELECT id, message, is_read, created_at FROM notification WHERE patient_id=? ORDER BY id DESC;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return notifications, errors.NewInternalServerError("error occured when preparing statement to fetch notifications", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(patientID)
+	if err != nil {
+		return notifications, errors.NewInternalServerError("error occured when executing statement to fetch notifications", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var notification Notification
+
+		if err := rows.Scan(&notification.ID, &notification.Message, &notification.Read, &notification.CreatedAt); err != nil {
+			return notifications, errors.NewInternalServerError("error occured when parsing notifications", err)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	query = "UPDATE notification SET is_read=1 WHERE patient_id=? AND is_read=0;"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return notifications, errors.NewInternalServerError("error occured when preparing statement to mark notifications read", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(patientID)
+	if err != nil {
+		return notifications, errors.NewInternalServerError("error occured when executing statement to mark notifications read", err)
+	}
+
+	return notifications, nil
+}
@@ -1,6 +1,17 @@
 package domain
 
+// Patient is a Patient account or a dependent profile managed by the
+// Patient account AccountID. Contact is how to reach the Patient, e.g. a phone
+// number.
 type Patient struct {
-	ID   int    `json:"patientId"`
-	Name string `json:"name"`
+	ID        int    `json:"patientId"`
+	Name      string `json:"name"`
+	AccountID int    `json:"accountId,omitempty"`
+	Contact   string `json:"contact,omitempty"`
+}
+
+// Dependent is a dependent profile with its upcoming appointments.
+type Dependent struct {
+	Patient
+	Appointments []Booking `json:"appointments"`
 }
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"appointment/errors"
+	"fmt"
+)
+
+func (ar *apptRepo) GetPatient(patientID int) (Patient, errors.AppointmentErr) {
+	patient := Patient{ID: patientID}
+
+	query := "SELECT name, COALESCE(account_id, 0), contact FROM patient WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return patient, errors.NewInternalServerError("error occured when preparing statement to fetch patient", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(patientID)
+	if err = result.Scan(&patient.Name, &patient.AccountID, &patient.Contact); err != nil {
+		return patient, errors.NewNotFoundError(fmt.Sprintf("Patient %d not found in database", patientID), err)
+	}
+
+	return patient, nil
+}
+
+// CreateDependent creates a dependent profile managed by the Patient account.
+func (ar *apptRepo) CreateDependent(accountID int, name string) (int, errors.AppointmentErr) {
+	var id int
+	query := "SELECT COUNT(id) FROM patient WHERE account_id=? AND name=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to check for existing dependent", err)
+	}
+	defer stmt.Close()
+
+	var count int
+
+	result := stmt.QueryRow(accountID, name)
+	if err = result.Scan(&count); err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to check for existing dependent", err)
+	}
+
+	if count != 0 {
+		return id, errors.NewGeneralError("dependent already exists", nil)
+	}
+
+	query = "INSERT INTO patient(name, account_id) VALUES (?, ?);"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to create dependent", err)
+	}
+	defer stmt.Close()
+
+	result2, err := stmt.Exec(name, accountID)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to create dependent", err)
+	}
+
+	newId, err := result2.LastInsertId()
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when getting dependent ID", err)
+	}
+
+	id = int(newId)
+
+	return id, nil
+}
+
+func (ar *apptRepo) GetDependents(accountID int) ([]Patient, errors.AppointmentErr) {
+	dependents := make([]Patient, 0)
+
+	query := "SELECT id, name, account_id FROM patient WHERE account_id=? ORDER BY id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return dependents, errors.NewInternalServerError("error occured when preparing statement to fetch dependents", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(accountID)
+	if err != nil {
+		return dependents, errors.NewInternalServerError("error occured when executing statement to fetch dependents", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dependent Patient
+
+		if err := rows.Scan(&dependent.ID, &dependent.Name, &dependent.AccountID); err != nil {
+			return dependents, errors.NewInternalServerError("error occured when parsing dependents", err)
+		}
+
+		dependents = append(dependents, dependent)
+	}
+
+	return dependents, nil
+}
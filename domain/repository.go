@@ -4,29 +4,90 @@ import (
 	"appointment/errors"
 	"database/sql"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 var Repo repoInterface = &apptRepo{}
 
+// dsnOptions makes transactions take the write lock when they begin, so
+// concurrent bookings queue up instead of failing to upgrade their lock.
+const dsnOptions = "?_txlock=immediate&_busy_timeout=5000"
+
 type repoInterface interface {
-	CreateDoctorAccount(string) (int, errors.AppointmentErr)
-	CreatePatientAccount(string) (int, errors.AppointmentErr)
+	CreateDoctorAccount(Doctor) (int, errors.AppointmentErr)
+	CreatePatientAccount(string, string) (int, errors.AppointmentErr)
+	GetPatient(int) (Patient, errors.AppointmentErr)
+	CreateDependent(int, string) (int, errors.AppointmentErr)
+	GetDependents(int) ([]Patient, errors.AppointmentErr)
+	CreateAdminAccount(string) (int, errors.AppointmentErr)
+	CreateDelegateAccount(int, string) (int, errors.AppointmentErr)
+	GetDelegateDoctor(int) (int, errors.AppointmentErr)
+	AdminExists(int) (bool, errors.AppointmentErr)
 	GetDoctorID(string) (int, errors.AppointmentErr)
+	GetDoctor(int) (Doctor, errors.AppointmentErr)
 	CheckScheduleExists(int, time.Time, time.Time) (bool, errors.AppointmentErr)
 	CheckScheduleOverlaps(int, time.Time, time.Time) (bool, errors.AppointmentErr)
-	AddSchedule(int, time.Time, time.Time) errors.AppointmentErr
+	AddSchedule(int, time.Time, time.Time, int, string) (int, errors.AppointmentErr)
+	GetSchedule(int) (Schedule, errors.AppointmentErr)
+	UpdateSchedule(Schedule) errors.AppointmentErr
 	CheckSlotAvailable(int, time.Time) (bool, errors.AppointmentErr)
 	CheckSlotWithinSchedule(int, time.Time) (bool, errors.AppointmentErr)
-	BookSlot(int, int, time.Time) (int, errors.AppointmentErr)
-	ListSchedule(int) ([]Appointment, errors.AppointmentErr)
-	CancelAppointment(int, int, string) errors.AppointmentErr
+	BookSlot(int, int, int, time.Time, string) (int, errors.AppointmentErr)
+	BookSlotFor(int, int, int, time.Time, string, int, string) (int, errors.AppointmentErr)
+	RequestSlot(int, int, int, time.Time, string, time.Time) (int, errors.AppointmentErr)
+	GetRequests(int) ([]Booking, errors.AppointmentErr)
+	ExpireRequests(time.Time) ([]Booking, errors.AppointmentErr)
+	ListSchedule(int, time.Time, time.Time) ([]Appointment, errors.AppointmentErr)
+	CancelAppointment(int, int, string, string, bool) errors.AppointmentErr
+	UpdateStatus(int, string, string, int, string) errors.AppointmentErr
+	MarkNoShows(time.Time) (int, errors.AppointmentErr)
+	GetNoShows(int, time.Time) (NoShowRecord, errors.AppointmentErr)
+	GetCancellations(int, time.Time, time.Time) ([]CancellationCount, []Booking, errors.AppointmentErr)
+	RequireDeposit(int) errors.AppointmentErr
+	GetBooking(int) (Booking, errors.AppointmentErr)
+	BookSeries(Series, int, string) (Series, errors.AppointmentErr)
+	GetSeriesBookings(int, time.Time) ([]Booking, errors.AppointmentErr)
+	RescheduleAppointments([]int, int, []time.Time, int, string) errors.AppointmentErr
+	CancelAppointments([]Booking, []bool, int, string, string) errors.AppointmentErr
+	GetPatientBookings(int, time.Time, time.Time) ([]Booking, errors.AppointmentErr)
+	GetAccountBookings(BookingFilter) ([]AccountBooking, int, errors.AppointmentErr)
+	RescheduleAppointment(int, int, time.Time, int, string, time.Time) errors.AppointmentErr
+	AddHolidays([]Holiday) (int, errors.AppointmentErr)
+	OptInHoliday(int, string) errors.AppointmentErr
+	GetHolidays(int, time.Time, time.Time) (map[string]string, errors.AppointmentErr)
+	GetDoctorSettings(int) (DoctorSettings, errors.AppointmentErr)
+	SaveDoctorSettings(DoctorSettings) errors.AppointmentErr
+	AddToWaitlist(WaitlistEntry) (int, errors.AppointmentErr)
+	GetWaitlist(int) ([]WaitlistEntry, errors.AppointmentErr)
+	GetWaitingForSlot(int, time.Time) ([]WaitlistEntry, errors.AppointmentErr)
+	UpdateWaitlistStatus(int, string, string, int) (bool, errors.AppointmentErr)
+	RemoveFromWaitlist(int, int) errors.AppointmentErr
+	AddNote(Note) (int, errors.AppointmentErr)
+	GetNotes(int) ([]Note, errors.AppointmentErr)
+	AddNotification(int, string) errors.AppointmentErr
+	GetNotifications(int) ([]Notification, errors.AppointmentErr)
+	HoldSlot(int, int, time.Time, time.Time, int) (int, errors.AppointmentErr)
+	GetHold(int) (Hold, errors.AppointmentErr)
+	ConfirmHold(int, int, time.Time) (int, errors.AppointmentErr)
+	ReleaseHold(int, int) errors.AppointmentErr
+	ExpireHolds(time.Time) ([]Hold, errors.AppointmentErr)
+	SearchSlots(SlotSearch) ([]Appointment, errors.AppointmentErr)
+	GetFreeBusy([]int, time.Time, time.Time) ([]FreeBusy, errors.AppointmentErr)
+	GetAgenda(int, time.Time, time.Time) ([]AgendaEntry, errors.AppointmentErr)
+	GetSchedules(int, time.Time, time.Time) ([]Schedule, errors.AppointmentErr)
+	GetCalendarFeed(int, string) (CalendarFeed, errors.AppointmentErr)
+	GetCalendarFeedBySecret(string) (CalendarFeed, errors.AppointmentErr)
+	SaveCalendarFeed(CalendarFeed) errors.AppointmentErr
+	GetFeedBookings(int, int, time.Time, time.Time) ([]FeedBooking, errors.AppointmentErr)
+	ReserveIdempotencyKey(IdempotencyRecord, time.Time) (IdempotencyRecord, bool, errors.AppointmentErr)
+	SaveIdempotentResponse(IdempotencyRecord) errors.AppointmentErr
+	ReleaseIdempotencyKey(string, string) errors.AppointmentErr
 	InitializeDB() *sql.DB
 	CloseDB()
 }
@@ -37,7 +98,7 @@ type apptRepo struct {
 
 func (ar *apptRepo) InitializeDB() *sql.DB {
 	var err error
-	ar.db, err = sql.Open("sqlite3", "./appointments.db")
+	ar.db, err = sql.Open("sqlite3", "./appointments.db"+dsnOptions)
 
 	if err != nil {
 		log.Fatal(err)
@@ -53,20 +114,6 @@ func (ar *apptRepo) InitializeDB() *sql.DB {
 	return ar.db
 }
 
-func AutoMigrate(db *sql.DB) error {
-	query, err := ioutil.ReadFile("database/schema.sql")
-	if err != nil {
-		return err
-	}
-
-	_, err = db.Exec(string(query))
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (ar *apptRepo) CloseDB() {
 	ar.db.Close()
 }
@@ -77,7 +124,7 @@ func NewAppointmentRepository(db *sql.DB) repoInterface {
 	}
 }
 
-func (ar *apptRepo) CreateDoctorAccount(name string) (int, errors.AppointmentErr) {
+func (ar *apptRepo) CreateDoctorAccount(doctor Doctor) (int, errors.AppointmentErr) {
 	var id int
 	query := "SELECT COUNT(id) FROM doctor WHERE name=?;"
 
@@ -89,7 +136,7 @@ func (ar *apptRepo) CreateDoctorAccount(name string) (int, errors.AppointmentErr
 
 	var count int
 
-	result := stmt.QueryRow(name)
+	result := stmt.QueryRow(doctor.Name)
 	if err = result.Scan(&count); err != nil {
 		return id, errors.NewInternalServerError("error occured when executing statement to check for existing doctor account", err)
 	}
@@ -98,7 +145,7 @@ func (ar *apptRepo) CreateDoctorAccount(name string) (int, errors.AppointmentErr
 		return id, errors.NewGeneralError("account already exists", nil)
 	}
 
-	query = "INSERT INTO doctor(name) VALUES (?);"
+	query = "INSERT INTO doctor(name, region, specialty) VALUES (?, ?, ?);"
 
 	stmt, err = ar.db.Prepare(query)
 	if err != nil {
@@ -106,7 +153,7 @@ func (ar *apptRepo) CreateDoctorAccount(name string) (int, errors.AppointmentErr
 	}
 	defer stmt.Close()
 
-	result2, err := stmt.Exec(name)
+	result2, err := stmt.Exec(doctor.Name, doctor.Region, doctor.Specialty)
 	if err != nil {
 		return id, errors.NewInternalServerError("error occured when executing statement to create new doctor account", err)
 	}
@@ -121,9 +168,9 @@ func (ar *apptRepo) CreateDoctorAccount(name string) (int, errors.AppointmentErr
 	return id, nil
 }
 
-func (ar *apptRepo) CreatePatientAccount(name string) (int, errors.AppointmentErr) {
+func (ar *apptRepo) CreatePatientAccount(name string, contact string) (int, errors.AppointmentErr) {
 	var id int
-	query := "SELECT COUNT(id) FROM patient WHERE name=?;"
+	query := "SELECT COUNT(id) FROM patient WHERE name=? AND account_id IS NULL;"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
@@ -142,7 +189,7 @@ func (ar *apptRepo) CreatePatientAccount(name string) (int, errors.AppointmentEr
 		return id, errors.NewGeneralError("account already exists", nil)
 	}
 
-	query = "INSERT INTO patient(name) VALUES (?);"
+	query = "INSERT INTO patient(name, contact) VALUES (?, ?);"
 
 	stmt, err = ar.db.Prepare(query)
 	if err != nil {
@@ -150,7 +197,7 @@ func (ar *apptRepo) CreatePatientAccount(name string) (int, errors.AppointmentEr
 	}
 	defer stmt.Close()
 
-	result2, err := stmt.Exec(name)
+	result2, err := stmt.Exec(name, contact)
 	if err != nil {
 		return id, errors.NewInternalServerError("error occured when executing statement to create new patient account", err)
 	}
@@ -184,6 +231,25 @@ func (ar *apptRepo) GetDoctorID(doctorName string) (int, errors.AppointmentErr)
 	return doctorID, nil
 }
 
+func (ar *apptRepo) GetDoctor(doctorID int) (Doctor, errors.AppointmentErr) {
+	doctor := Doctor{ID: doctorID}
+
+	query := "SELECT name, region, specialty FROM doctor WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return doctor, errors.NewInternalServerError("error occured when preparing statement to fetch doctor", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(doctorID)
+	if err = result.Scan(&doctor.Name, &doctor.Region, &doctor.Specialty); err != nil {
+		return doctor, errors.NewNotFoundError(fmt.Sprintf("Doctor %d not found in database", doctorID), err)
+	}
+
+	return doctor, nil
+}
+
 func (ar *apptRepo) CheckScheduleExists(doctorID int, startTime, endTime time.Time) (bool, errors.AppointmentErr) {
 	query := "SELECT COUNT(id) FROM doctor_schedule WHERE doctor_id=? and start_time=? and end_time=?;"
 
@@ -207,8 +273,10 @@ func (ar *apptRepo) CheckScheduleExists(doctorID int, startTime, endTime time.Ti
 	return true, nil
 }
 
+// CheckScheduleOverlaps checks if a schedule of the Doctor overlaps the time
+// from startTime until endTime.
 func (ar *apptRepo) CheckScheduleOverlaps(doctorID int, startTime, endTime time.Time) (bool, errors.AppointmentErr) {
-	query := "SELECT start_time, end_time FROM doctor_schedule WHERE doctor_id=? AND start_time>=DATE('now') and end_time<=DATE('now', '+1 day');"
+	query := "SELECT COUNT(id) FROM doctor_schedule WHERE doctor_id=? AND start_time<? AND end_time>?;"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
@@ -216,48 +284,43 @@ func (ar *apptRepo) CheckScheduleOverlaps(doctorID int, startTime, endTime time.
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(doctorID)
-	if err != nil {
-		return false, errors.NewInternalServerError("error occured when executing statement to fetch Doctor schedule", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var start_time time.Time
-		var end_time time.Time
-
-		err := rows.Scan(&start_time, &end_time)
-		if err != nil {
-			return false, errors.NewInternalServerError("error occured when parsing Doctor schedule", err)
-		}
+	var count int
 
-		if (startTime.Equal(start_time)) || (endTime.Equal(end_time)) || (startTime.After(start_time) && startTime.Before(end_time)) || (endTime.Before(end_time) && endTime.After(start_time)) {
-			return true, nil
-		}
+	result := stmt.QueryRow(doctorID, endTime, startTime)
+	if err = result.Scan(&count); err != nil {
+		return false, errors.NewInternalServerError("error occured when executing statement to fetch Doctor schedule", err)
 	}
 
-	return false, nil
+	return count > 0, nil
 }
 
-func (ar *apptRepo) AddSchedule(doctorID int, startTime time.Time, endTime time.Time) errors.AppointmentErr {
-	query := "INSERT INTO doctor_schedule (doctor_id, start_time, end_time) VALUES (?,?,?);"
+func (ar *apptRepo) AddSchedule(doctorID int, startTime time.Time, endTime time.Time, capacity int, appointmentType string) (int, errors.AppointmentErr) {
+	query := "INSERT INTO doctor_schedule (doctor_id, start_time, end_time, capacity, appointment_type) VALUES (?,?,?,?,?);"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
-		return errors.NewInternalServerError("error occured when preparing statement to create Doctor schedule in database", err)
+		return 0, errors.NewInternalServerError("error occured when preparing statement to create Doctor schedule in database", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(doctorID, startTime, endTime)
+	result, err := stmt.Exec(doctorID, startTime, endTime, capacity, appointmentType)
 	if err != nil {
-		return errors.NewInternalServerError("error occured when executing statement to create Doctor schedule in database", err)
+		return 0, errors.NewInternalServerError("error occured when executing statement to create Doctor schedule in database", err)
 	}
 
-	return nil
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.NewInternalServerError("error occured when getting schedule ID", err)
+	}
+
+	return int(id), nil
 }
 
+// CheckSlotAvailable checks if the slot has capacity left for another
+// appointment, counting held seats as taken. Slots outside the Doctor
+// schedule have a capacity of 1.
 func (ar *apptRepo) CheckSlotAvailable(doctorID int, startTime time.Time) (bool, errors.AppointmentErr) {
-	query := "SELECT count(id) FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time=?;"
+	query := "SELECT (SELECT COUNT(id) FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time=?) + (SELECT COUNT(id) FROM slot_hold WHERE doctor_id=? AND start_time=? AND status='held' AND expires_at>?), COALESCE((SELECT capacity FROM doctor_schedule WHERE doctor_id=? AND start_time<=? AND end_time>? LIMIT 1), 1);"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
@@ -265,14 +328,14 @@ func (ar *apptRepo) CheckSlotAvailable(doctorID int, startTime time.Time) (bool,
 	}
 	defer stmt.Close()
 
-	var count int
+	var count, capacity int
 
-	result := stmt.QueryRow(doctorID, startTime)
-	if err = result.Scan(&count); err != nil {
+	result := stmt.QueryRow(doctorID, startTime, doctorID, startTime, time.Now().UTC(), doctorID, startTime, startTime)
+	if err = result.Scan(&count, &capacity); err != nil {
 		return false, errors.NewInternalServerError("error occured when executing statement to check for available slots in database", err)
 	}
 
-	if count != 0 {
+	if count >= capacity {
 		return false, nil
 	}
 
@@ -280,7 +343,8 @@ func (ar *apptRepo) CheckSlotAvailable(doctorID int, startTime time.Time) (bool,
 }
 
 func (ar *apptRepo) CheckSlotWithinSchedule(doctorID int, startTime time.Time) (bool, errors.AppointmentErr) {
-	query := "SELECT start_time, end_time FROM doctor_schedule WHERE doctor_id=? AND start_time>=DATE('now') and end_time<=DATE('now', '+1 day');"
+	// Schedules of the day of the slot
+	query := "SELECT start_time, end_time FROM doctor_schedule WHERE doctor_id=? AND start_time>=DATE(?) and end_time<=DATE(?, '+1 day');"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
@@ -288,7 +352,7 @@ func (ar *apptRepo) CheckSlotWithinSchedule(doctorID int, startTime time.Time) (
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(doctorID)
+	rows, err := stmt.Query(doctorID, startTime.UTC(), startTime.UTC())
 	if err != nil {
 		return false, errors.NewInternalServerError("error occured when executing statement to fetch Doctor schedule", err)
 	}
@@ -311,37 +375,127 @@ func (ar *apptRepo) CheckSlotWithinSchedule(doctorID int, startTime time.Time) (
 	return false, nil
 }
 
-func (ar *apptRepo) BookSlot(doctorID int, userID int, startTime time.Time) (int, errors.AppointmentErr) {
-	var appointmentID int
-
-	query := "INSERT INTO appointments(doctor_id, patient_id, start_time, is_active) VALUES (?, ?, ?, 1);"
+// BookSlot books a seat in the slot for the Patient within a transaction, so
+// the capacity check and the insert cannot interleave with another booking.
+// bookedBy is the account booking it. A full slot results in a Conflict error.
+func (ar *apptRepo) BookSlot(doctorID int, patientID int, bookedBy int, startTime time.Time, reason string) (int, errors.AppointmentErr) {
+	return ar.BookSlotFor(doctorID, patientID, bookedBy, startTime, reason, 0, "")
+}
 
-	stmt, err := ar.db.Prepare(query)
+// BookSlotFor books a seat in the slot like BookSlot, on behalf of the Patient
+// by the Doctor or delegate actorID, if not 0.
+func (ar *apptRepo) BookSlotFor(doctorID int, patientID int, bookedBy int, startTime time.Time, reason string, actorID int, actorType string) (int, errors.AppointmentErr) {
+	tx, err := ar.db.Begin()
 	if err != nil {
-		return appointmentID, errors.NewInternalServerError("error occured when preparing statement for booking slot in database", err)
+		return 0, errors.NewInternalServerError("error occured when starting transaction for booking slot in database", err)
+	}
+	defer tx.Rollback()
+
+	appointmentID, appErr := insertAppointment(tx, doctorID, patientID, bookedBy, startTime, reason, 0, actorID, actorType)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.NewInternalServerError("error occured when committing booked slot", err)
+	}
+
+	return appointmentID, nil
+}
+
+// insertAppointment books a free seat in the slot within the transaction.
+// seriesID links the appointment to its recurring series, if any, and actorID
+// is the Doctor or delegate booking on behalf of the Patient, if any.
+func insertAppointment(tx *sql.Tx, doctorID int, patientID int, bookedBy int, startTime time.Time, reason string, seriesID int, actorID int, actorType string) (int, errors.AppointmentErr) {
+	seat, appErr := freeSeat(tx, doctorID, startTime)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	var series interface{}
+	if seriesID != 0 {
+		series = seriesID
+	}
+
+	var actor, actorKind interface{}
+	if actorID != 0 {
+		actor, actorKind = actorID, actorType
 	}
-	defer stmt.Close()
 
-	result, err := stmt.Exec(doctorID, userID, startTime)
+	query := "INSERT INTO appointments(doctor_id, patient_id, booked_by, start_time, is_active, seat, duration_minutes, reason, status, confirmed_at, series_id, initiated_by, initiated_by_type) VALUES (?, ?, ?, ?, 1, ?, ?, ?, ?, ?, ?, ?, ?);"
+
+	result, err := tx.Exec(query, doctorID, patientID, bookedBy, startTime, seat, SlotMinutes, reason, StatusConfirmed, time.Now().UTC(), series, actor, actorKind)
 	if err != nil {
-		return appointmentID, errors.NewInternalServerError("error occured when executing statement for booking slot in database", err)
+		if isUniqueViolation(err) {
+			return 0, errors.NewConflictError("Slot already taken", nil)
+		}
+
+		return 0, errors.NewInternalServerError("error occured when executing statement for booking slot in database", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return appointmentID, errors.NewInternalServerError("error occured when getting appointment ID", err)
+		return 0, errors.NewInternalServerError("error occured when getting appointment ID", err)
 	}
 
-	appointmentID = int(id)
+	return int(id), nil
+}
+
+// freeSeat returns the lowest seat of the slot not taken by an active
+// appointment or hold, or a Conflict error if the slot is at capacity.
+func freeSeat(tx *sql.Tx, doctorID int, startTime time.Time) (int, errors.AppointmentErr) {
+	query := "SELECT COALESCE((SELECT capacity FROM doctor_schedule WHERE doctor_id=? AND start_time<=? AND end_time>? LIMIT 1), 1);"
 
-	return appointmentID, nil
+	var capacity int
+
+	if err := tx.QueryRow(query, doctorID, startTime, startTime).Scan(&capacity); err != nil {
+		return 0, errors.NewInternalServerError("error occured when fetching slot capacity", err)
+	}
+
+	query = "SELECT seat FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time=? UNION SELECT seat FROM slot_hold WHERE doctor_id=? AND start_time=? AND status='held' AND expires_at>?;"
+
+	rows, err := tx.Query(query, doctorID, startTime, doctorID, startTime, time.Now().UTC())
+	if err != nil {
+		return 0, errors.NewInternalServerError("error occured when fetching booked seats", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[int]bool)
+
+	for rows.Next() {
+		var seat int
+
+		if err := rows.Scan(&seat); err != nil {
+			return 0, errors.NewInternalServerError("error occured when parsing booked seats", err)
+		}
+
+		taken[seat] = true
+	}
+
+	for seat := 1; seat <= capacity; seat++ {
+		if !taken[seat] {
+			return seat, nil
+		}
+	}
+
+	return 0, errors.NewConflictError("Slot already taken", nil)
 }
 
-func (ar *apptRepo) ListSchedule(doctorID int) ([]Appointment, errors.AppointmentErr) {
+func isUniqueViolation(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+
+	return ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// ListSchedule returns the slots of the Doctor schedule starting from from
+// until to, with their bookings and holds.
+func (ar *apptRepo) ListSchedule(doctorID int, from time.Time, to time.Time) ([]Appointment, errors.AppointmentErr) {
 	appointments := make([]Appointment, 0)
 
+	from, to = from.UTC(), to.UTC()
+
 	// Get Booked Appointments
-	query := "SELECT id, patient_id, start_time FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time>=DATE('now') AND start_time<=DATE('now', '+1 day') ORDER BY start_time;"
+	query := "SELECT id, patient_id, start_time, status FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time>=? AND start_time<? ORDER BY start_time;"
 
 	stmt, err := ar.db.Prepare(query)
 	if err != nil {
@@ -349,34 +503,64 @@ func (ar *apptRepo) ListSchedule(doctorID int) ([]Appointment, errors.Appointmen
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(doctorID)
+	rows, err := stmt.Query(doctorID, from, to)
 	if err != nil {
 		return appointments, errors.NewInternalServerError("error occured when executing statement to fetch Booked Appointments", err)
 	}
 	defer rows.Close()
 
-	bookedAppointments := make(map[time.Time]struct {
+	type booking struct {
 		AppointmentID int
 		PatientID     int
-	})
+		Status        string
+	}
+
+	bookedAppointments := make(map[time.Time][]booking)
 
 	for rows.Next() {
 		var aptID, patID int
 		var st time.Time
+		var status string
 
-		err := rows.Scan(&aptID, &patID, &st)
+		err := rows.Scan(&aptID, &patID, &st, &status)
 		if err != nil {
 			return appointments, errors.NewInternalServerError("error occured when parsing Booked Appointments", err)
 		}
 
-		bookedAppointments[st] = struct {
-			AppointmentID int
-			PatientID     int
-		}{aptID, patID}
+		bookedAppointments[st] = append(bookedAppointments[st], booking{aptID, patID, status})
+	}
+
+	// Get Held Slots
+	query = "SELECT start_time, COUNT(id) FROM slot_hold WHERE doctor_id=? AND status='held' AND expires_at>? AND start_time>=? AND start_time<? GROUP BY start_time;"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return appointments, errors.NewInternalServerError("error occured when preparing statement to fetch Held Slots", err)
+	}
+	defer stmt.Close()
+
+	rows, err = stmt.Query(doctorID, time.Now().UTC(), from, to)
+	if err != nil {
+		return appointments, errors.NewInternalServerError("error occured when executing statement to fetch Held Slots", err)
+	}
+	defer rows.Close()
+
+	heldSlots := make(map[time.Time]int)
+
+	for rows.Next() {
+		var st time.Time
+		var count int
+
+		err := rows.Scan(&st, &count)
+		if err != nil {
+			return appointments, errors.NewInternalServerError("error occured when parsing Held Slots", err)
+		}
+
+		heldSlots[st] = count
 	}
 
 	// Get Schedule
-	query = "SELECT start_time, end_time FROM doctor_schedule WHERE doctor_id=? AND start_time>=DATE('now') AND end_time<=DATE('now', '+1 day') ORDER BY start_time;"
+	query = "SELECT start_time, end_time, capacity, appointment_type FROM doctor_schedule WHERE doctor_id=? AND end_time>? AND start_time<? ORDER BY start_time;"
 
 	stmt, err = ar.db.Prepare(query)
 	if err != nil {
@@ -384,7 +568,7 @@ func (ar *apptRepo) ListSchedule(doctorID int) ([]Appointment, errors.Appointmen
 	}
 	defer stmt.Close()
 
-	rows, err = stmt.Query(doctorID)
+	rows, err = stmt.Query(doctorID, from, to)
 	if err != nil {
 		return appointments, errors.NewInternalServerError("error occured when executing statement to fetch Doctor schedule", err)
 	}
@@ -393,76 +577,111 @@ func (ar *apptRepo) ListSchedule(doctorID int) ([]Appointment, errors.Appointmen
 	for rows.Next() {
 		var start_time time.Time
 		var end_time time.Time
+		var capacity int
+		var appointmentType string
 
-		err := rows.Scan(&start_time, &end_time)
+		err := rows.Scan(&start_time, &end_time, &capacity, &appointmentType)
 		if err != nil {
 			return appointments, errors.NewInternalServerError("error occured when parsing Doctor schedule", err)
 		}
 
 		t := start_time
-		for t.Before(end_time) {
+		for ; t.Before(end_time); t = t.Add(SlotMinutes * time.Minute) {
+			// Schedules may extend beyond the range
+			if t.Before(from) || !t.Before(to) {
+				continue
+			}
+
 			doctorID := strconv.Itoa(doctorID)
 
 			appointment := Appointment{
-				ID:        "",
-				DoctorID:  doctorID,
-				PatientID: "",
-				StartTime: t,
-				Booked:    false,
+				ID:              "",
+				DoctorID:        doctorID,
+				PatientID:       "",
+				StartTime:       t,
+				Booked:          false,
+				Capacity:        capacity,
+				Remaining:       capacity,
+				AppointmentType: appointmentType,
 			}
 
 			if data, ok := bookedAppointments[t]; ok {
-				appointment.ID = strconv.Itoa(data.AppointmentID)
-				appointment.PatientID = strconv.Itoa(data.PatientID)
-				appointment.Booked = true
+				// Individual bookings are only shown for single patient slots
+				if capacity == 1 {
+					appointment.ID = strconv.Itoa(data[0].AppointmentID)
+					appointment.PatientID = strconv.Itoa(data[0].PatientID)
+					appointment.Status = data[0].Status
+				}
+
+				appointment.Remaining -= len(data)
 			}
 
-			appointments = append(appointments, appointment)
+			appointment.Remaining -= heldSlots[t]
+			if appointment.Remaining < 0 {
+				appointment.Remaining = 0
+			}
 
-			t = t.Add(15 * time.Minute)
+			appointment.Booked = appointment.Remaining == 0
 
+			appointments = append(appointments, appointment)
 		}
 	}
 
 	return appointments, nil
 }
 
-func (ar *apptRepo) CancelAppointment(appointmentID int, userID int, userType string) errors.AppointmentErr {
-	query := "SELECT doctor_id, patient_id, is_active FROM appointments WHERE id=?;"
-
-	stmt, err := ar.db.Prepare(query)
-	if err != nil {
-		errors.NewInternalServerError("error occured when preparing statement to check for appointment status", err)
-	}
-	defer stmt.Close()
-
-	var doctorID int
-	var patientID int
-	var activeStatus int
+// CancelAppointment cancels the appointment on behalf of the user, recording
+// the reason and whether the cancellation was late.
+func (ar *apptRepo) CancelAppointment(appointmentID int, userID int, userType string, reason string, late bool) errors.AppointmentErr {
+	booking, appErr := ar.GetBooking(appointmentID)
+	if appErr != nil {
+		if appErr.GetStatus() == http.StatusNotFound {
+			return errors.NewGeneralError(appErr.GetMessage(), nil)
+		}
 
-	result := stmt.QueryRow(appointmentID)
-	if err = result.Scan(&doctorID, &patientID, &activeStatus); err != nil {
-		return errors.NewBadRequestError(fmt.Sprintf("appointment id %d does not exist in database", appointmentID), err)
+		return appErr
 	}
 
-	if activeStatus == 0 {
+	if !booking.Active {
 		return errors.NewGeneralError(fmt.Sprintf("appointment id %d is already cancelled", appointmentID), nil)
 	}
 
-	if (userType == "doctor" && userID != doctorID) || (userType == "patient" && userID != patientID) {
+	if (userType == "doctor" && userID != booking.DoctorID) || (userType == "patient" && !booking.ManagedBy(userID)) {
 		return errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
 	}
 
-	query = "UPDATE appointments SET deleted_at=CURRENT_TIMESTAMP, is_active=0 WHERE id=?;"
+	// Patients cannot cancel once checked in
+	if !booking.Open() && (userType == "patient" || !CanTransition(booking.Status, StatusCancelled)) {
+		return errors.NewGeneralError(fmt.Sprintf("appointment id %d is %s and cannot be cancelled", appointmentID, booking.Status), nil)
+	}
 
-	stmt, err = ar.db.Prepare(query)
+	tx, err := ar.db.Begin()
 	if err != nil {
-		return errors.NewInternalServerError("error occured when preparing statement to cancel slot", err)
+		return errors.NewInternalServerError("error occured when starting transaction to cancel slot", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	_, err = stmt.Exec(appointmentID)
-	if err != nil {
+	if appErr := cancelAppointment(tx, booking, userID, userType, reason, late); appErr != nil {
+		return appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing cancelled slot", err)
+	}
+
+	return nil
+}
+
+// cancelAppointment cancels the booking within the transaction, unless its
+// status changed since it was read.
+func cancelAppointment(tx *sql.Tx, booking Booking, userID int, userType string, reason string, late bool) errors.AppointmentErr {
+	if appErr := updateStatus(tx, booking.ID, booking.Status, StatusCancelled, userID, userType); appErr != nil {
+		return appErr
+	}
+
+	query := "UPDATE appointments SET cancellation_reason=?, cancelled_by=?, cancelled_by_type=?, late_cancellation=? WHERE id=?;"
+
+	if _, err := tx.Exec(query, reason, userID, userType, late, booking.ID); err != nil {
 		return errors.NewInternalServerError("error occured when executing statement to cancel slot", err)
 	}
 
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"appointment/errors"
+	"time"
+)
+
+// RequestSlot books a seat in the slot like BookSlot, as a request pending
+// the approval of the Doctor until expiresAt. The seat stays taken meanwhile.
+func (ar *apptRepo) RequestSlot(doctorID int, patientID int, bookedBy int, startTime time.Time, reason string, expiresAt time.Time) (int, errors.AppointmentErr) {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return 0, errors.NewInternalServerError("error occured when starting transaction for requesting slot in database", err)
+	}
+	defer tx.Rollback()
+
+	appointmentID, appErr := insertAppointment(tx, doctorID, patientID, bookedBy, startTime, reason, 0, 0, "")
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	query := "UPDATE appointments SET status=?, confirmed_at=NULL, request_expires_at=? WHERE id=?;"
+
+	if _, err = tx.Exec(query, StatusRequested, expiresAt.UTC(), appointmentID); err != nil {
+		return 0, errors.NewInternalServerError("error occured when executing statement for requesting slot in database", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.NewInternalServerError("error occured when committing requested slot", err)
+	}
+
+	return appointmentID, nil
+}
+
+// GetRequests returns the requests pending the approval of the Doctor, in
+// order of start time.
+func (ar *apptRepo) GetRequests(doctorID int) ([]Booking, errors.AppointmentErr) {
+	bookings := make([]Booking, 0)
+
+	query := "SELECT " + bookingColumns + " FROM appointments a LEFT JOIN patient p ON p.id=a.patient_id WHERE a.doctor_id=? AND a.status=? ORDER BY a.start_time, a.id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when preparing statement to fetch booking requests", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(doctorID, StatusRequested)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when executing statement to fetch booking requests", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking Booking
+
+		if err := scanBooking(rows, &booking); err != nil {
+			return bookings, errors.NewInternalServerError("error occured when parsing booking requests", err)
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// ExpireRequests cancels the requests still pending at now, freeing their
+// seats, and returns them.
+func (ar *apptRepo) ExpireRequests(now time.Time) ([]Booking, errors.AppointmentErr) {
+	expired := make([]Booking, 0)
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return expired, errors.NewInternalServerError("error occured when starting transaction to expire booking requests", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT " + bookingColumns + " FROM appointments a LEFT JOIN patient p ON p.id=a.patient_id WHERE a.status=? AND a.request_expires_at<=?;"
+
+	rows, err := tx.Query(query, StatusRequested, now.UTC())
+	if err != nil {
+		return expired, errors.NewInternalServerError("error occured when executing statement to fetch expired booking requests", err)
+	}
+
+	for rows.Next() {
+		var booking Booking
+
+		if err := scanBooking(rows, &booking); err != nil {
+			rows.Close()
+
+			return expired, errors.NewInternalServerError("error occured when parsing expired booking requests", err)
+		}
+
+		expired = append(expired, booking)
+	}
+	rows.Close()
+
+	for _, booking := range expired {
+		if appErr := updateStatus(tx, booking.ID, StatusRequested, StatusCancelled, 0, "system"); appErr != nil {
+			return expired, appErr
+		}
+
+		query = "UPDATE appointments SET cancellation_reason=?, cancelled_by=0, cancelled_by_type='system' WHERE id=?;"
+
+		if _, err := tx.Exec(query, "Request expired", booking.ID); err != nil {
+			return expired, errors.NewInternalServerError("error occured when executing statement to expire booking request", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return expired, errors.NewInternalServerError("error occured when committing expired booking requests", err)
+	}
+
+	return expired, nil
+}
@@ -2,9 +2,48 @@ package domain
 
 import "time"
 
+// Schedule is a block of the Doctor availability. Capacity is the number of
+// patients that can book each slot of the block.
 type Schedule struct {
-	ID        int       `json:"scheduleId"`
-	DoctorID  int       `json:"doctorId"`
-	StartTime time.Time `json:"starttime"`
-	EndTime   time.Time `json:"endtime"`
+	ID              int       `json:"scheduleId"`
+	DoctorID        int       `json:"doctorId"`
+	StartTime       time.Time `json:"starttime"`
+	EndTime         time.Time `json:"endtime"`
+	Capacity        int       `json:"capacity"`
+	AppointmentType string    `json:"appointmenttype"`
+}
+
+const (
+	SlotsFree   = "free"
+	SlotsBooked = "booked"
+)
+
+// ScheduleFilter selects the slots of the Doctor schedule starting from From
+// until To. Only lists free slots, with seats left, or booked ones, that are
+// full, when set. A zero Limit lists all slots from Offset.
+type ScheduleFilter struct {
+	From            time.Time
+	To              time.Time
+	Only            string
+	AppointmentType string
+	Limit           int
+	Offset          int
+}
+
+// ScheduleSummary counts the slots of a date range before they are narrowed
+// down to free or booked ones, for calendar views. Every slot is either free
+// or booked.
+type ScheduleSummary struct {
+	Slots  int          `json:"slots"`
+	Free   int          `json:"free"`
+	Booked int          `json:"booked"`
+	Days   []DaySummary `json:"days"`
+}
+
+// DaySummary counts the slots of a day of the schedule.
+type DaySummary struct {
+	Date   string `json:"date"`
+	Slots  int    `json:"slots"`
+	Free   int    `json:"free"`
+	Booked int    `json:"booked"`
 }
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+func (ar *apptRepo) GetSchedule(scheduleID int) (Schedule, errors.AppointmentErr) {
+	schedule := Schedule{ID: scheduleID}
+
+	query := "SELECT doctor_id, start_time, end_time, capacity, appointment_type FROM doctor_schedule WHERE id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return schedule, errors.NewInternalServerError("error occured when preparing statement to fetch Doctor schedule", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(scheduleID)
+	if err = result.Scan(&schedule.DoctorID, &schedule.StartTime, &schedule.EndTime, &schedule.Capacity, &schedule.AppointmentType); err != nil {
+		return schedule, errors.NewNotFoundError(fmt.Sprintf("schedule id %d does not exist in database", scheduleID), err)
+	}
+
+	return schedule, nil
+}
+
+// UpdateSchedule replaces the times, capacity and appointment type of the
+// schedule within a transaction. It results in a Conflict error if the
+// schedule would overlap another one of the Doctor, or leave out appointments
+// or holds taken in it.
+func (ar *apptRepo) UpdateSchedule(schedule Schedule) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to update Doctor schedule", err)
+	}
+	defer tx.Rollback()
+
+	var current Schedule
+
+	query := "SELECT start_time, end_time FROM doctor_schedule WHERE id=? AND doctor_id=?;"
+
+	if err := tx.QueryRow(query, schedule.ID, schedule.DoctorID).Scan(&current.StartTime, &current.EndTime); err != nil {
+		return errors.NewNotFoundError(fmt.Sprintf("schedule id %d does not exist in database", schedule.ID), err)
+	}
+
+	var overlapping int
+
+	query = "SELECT COUNT(id) FROM doctor_schedule WHERE doctor_id=? AND id<>? AND start_time<? AND end_time>?;"
+
+	if err := tx.QueryRow(query, schedule.DoctorID, schedule.ID, schedule.EndTime.UTC(), schedule.StartTime.UTC()).Scan(&overlapping); err != nil {
+		return errors.NewInternalServerError("error occured when checking for overlapping schedules", err)
+	}
+
+	if overlapping != 0 {
+		return errors.NewConflictError("Schedule overlaps with existing schedule", nil)
+	}
+
+	// Seats are numbered up to the capacity, so taken ones must stay within it
+	query = "SELECT start_time, seat FROM appointments WHERE doctor_id=? AND is_active=1 AND start_time>=? AND start_time<? UNION ALL SELECT start_time, seat FROM slot_hold WHERE doctor_id=? AND status='held' AND expires_at>? AND start_time>=? AND start_time<?;"
+
+	rows, err := tx.Query(query, schedule.DoctorID, current.StartTime, current.EndTime, schedule.DoctorID, time.Now().UTC(), current.StartTime, current.EndTime)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to fetch booked seats", err)
+	}
+
+	for rows.Next() {
+		var st time.Time
+		var seat int
+
+		if err := rows.Scan(&st, &seat); err != nil {
+			rows.Close()
+
+			return errors.NewInternalServerError("error occured when parsing booked seats", err)
+		}
+
+		if st.Before(schedule.StartTime) || !st.Before(schedule.EndTime) {
+			rows.Close()
+
+			return errors.NewConflictError(fmt.Sprintf("Slot at %s is booked and must stay within the schedule", st.UTC().Format(time.RFC3339)), nil)
+		}
+
+		if seat > schedule.Capacity {
+			rows.Close()
+
+			return errors.NewConflictError(fmt.Sprintf("Slot at %s has more seats booked than the new capacity", st.UTC().Format(time.RFC3339)), nil)
+		}
+	}
+	rows.Close()
+
+	query = "UPDATE doctor_schedule SET start_time=?, end_time=?, capacity=?, appointment_type=? WHERE id=?;"
+
+	if _, err := tx.Exec(query, schedule.StartTime.UTC(), schedule.EndTime.UTC(), schedule.Capacity, schedule.AppointmentType, schedule.ID); err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to update Doctor schedule", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing Doctor schedule", err)
+	}
+
+	return nil
+}
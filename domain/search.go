@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// SlotSearch filters the free slots to search for. Empty filters match
+// everything. FromMinute and ToMinute bound the time of day of the slots, in
+// minutes after midnight in the location of From.
+type SlotSearch struct {
+	DoctorIDs       []int
+	Specialty       string
+	AppointmentType string
+	From            time.Time
+	To              time.Time
+	FromMinute      int
+	ToMinute        int
+}
+
+// WithinTimeOfDay checks if the slot at startTime is within the time of day
+// bounds of the search.
+func (s SlotSearch) WithinTimeOfDay(startTime time.Time) bool {
+	local := startTime.In(s.From.Location())
+	minute := local.Hour()*60 + local.Minute()
+
+	return minute >= s.FromMinute && minute < s.ToMinute
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"appointment/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type slotKey struct {
+	DoctorID  int
+	StartTime int64
+}
+
+// SearchSlots returns the slots with remaining capacity between search.From
+// and search.To of the Doctors matching the search, in order of start time.
+// Bookings and holds of all matching Doctors are fetched in one query each.
+func (ar *apptRepo) SearchSlots(search SlotSearch) ([]Appointment, errors.AppointmentErr) {
+	slots := make([]Appointment, 0)
+
+	from, to := search.From.UTC(), search.To.UTC()
+
+	// Get Schedules
+	query := "SELECT s.doctor_id, d.name, s.start_time, s.end_time, s.capacity, s.appointment_type FROM doctor_schedule s JOIN doctor d ON d.id=s.doctor_id WHERE s.end_time>? AND s.start_time<?"
+	args := []interface{}{from, to}
+
+	if len(search.Specialty) != 0 {
+		query += " AND d.specialty=? COLLATE NOCASE"
+		args = append(args, search.Specialty)
+	}
+
+	if len(search.AppointmentType) != 0 {
+		query += " AND s.appointment_type=? COLLATE NOCASE"
+		args = append(args, search.AppointmentType)
+	}
+
+	if len(search.DoctorIDs) != 0 {
+		query += " AND s.doctor_id IN (" + placeholders(len(search.DoctorIDs)) + ")"
+		for _, id := range search.DoctorIDs {
+			args = append(args, id)
+		}
+	}
+
+	query += " ORDER BY s.start_time;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return slots, errors.NewInternalServerError("error occured when preparing statement to search Doctor schedules", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return slots, errors.NewInternalServerError("error occured when executing statement to search Doctor schedules", err)
+	}
+	defer rows.Close()
+
+	doctorIDs := make([]interface{}, 0)
+	seen := make(map[int]bool)
+
+	type block struct {
+		Appointment
+		EndTime time.Time
+		Doctor  int
+	}
+
+	blocks := make([]block, 0)
+
+	for rows.Next() {
+		var b block
+
+		if err := rows.Scan(&b.Doctor, &b.DoctorName, &b.StartTime, &b.EndTime, &b.Capacity, &b.AppointmentType); err != nil {
+			return slots, errors.NewInternalServerError("error occured when parsing Doctor schedules", err)
+		}
+
+		blocks = append(blocks, b)
+
+		if !seen[b.Doctor] {
+			seen[b.Doctor] = true
+			doctorIDs = append(doctorIDs, b.Doctor)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return slots, nil
+	}
+
+	// Get Booked and Held Slots
+	taken := make(map[slotKey]int)
+
+	query = "SELECT doctor_id, start_time, COUNT(id) FROM appointments WHERE is_active=1 AND start_time>=? AND start_time<? AND doctor_id IN (" + placeholders(len(doctorIDs)) + ") GROUP BY doctor_id, start_time UNION ALL SELECT doctor_id, start_time, COUNT(id) FROM slot_hold WHERE status='held' AND expires_at>? AND start_time>=? AND start_time<? AND doctor_id IN (" + placeholders(len(doctorIDs)) + ") GROUP BY doctor_id, start_time;"
+
+	args = append([]interface{}{from, to}, doctorIDs...)
+	args = append(args, time.Now().UTC(), from, to)
+	args = append(args, doctorIDs...)
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return slots, errors.NewInternalServerError("error occured when preparing statement to fetch Booked Slots", err)
+	}
+	defer stmt.Close()
+
+	rows, err = stmt.Query(args...)
+	if err != nil {
+		return slots, errors.NewInternalServerError("error occured when executing statement to fetch Booked Slots", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doctorID, count int
+		var st time.Time
+
+		if err := rows.Scan(&doctorID, &st, &count); err != nil {
+			return slots, errors.NewInternalServerError("error occured when parsing Booked Slots", err)
+		}
+
+		taken[slotKey{doctorID, st.Unix()}] += count
+	}
+
+	for _, b := range blocks {
+		for t := b.StartTime; t.Before(b.EndTime); t = t.Add(SlotMinutes * time.Minute) {
+			if t.Before(from) || !t.Before(to) {
+				continue
+			}
+
+			if !search.WithinTimeOfDay(t) {
+				continue
+			}
+
+			remaining := b.Capacity - taken[slotKey{b.Doctor, t.Unix()}]
+			if remaining <= 0 {
+				continue
+			}
+
+			slots = append(slots, Appointment{
+				DoctorID:        strconv.Itoa(b.Doctor),
+				DoctorName:      b.DoctorName,
+				StartTime:       t,
+				Capacity:        b.Capacity,
+				Remaining:       remaining,
+				AppointmentType: b.AppointmentType,
+			})
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+
+	return slots, nil
+}
+
+// placeholders returns n comma separated query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
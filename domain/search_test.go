@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlotSearch_WithinTimeOfDay(t *testing.T) {
+	t.Parallel()
+
+	berlin := time.FixedZone("CEST", 2*60*60)
+
+	tests := []struct {
+		name      string
+		from      time.Time
+		startTime time.Time
+		want      bool
+	}{
+		{
+			name:      "Within UTC",
+			from:      time.Date(2021, 7, 18, 0, 0, 0, 0, time.UTC),
+			startTime: time.Date(2021, 7, 18, 9, 0, 0, 0, time.UTC),
+			want:      true,
+		},
+		{
+			// The window ends before totime
+			name:      "At End",
+			from:      time.Date(2021, 7, 18, 0, 0, 0, 0, time.UTC),
+			startTime: time.Date(2021, 7, 18, 12, 0, 0, 0, time.UTC),
+			want:      false,
+		},
+		{
+			// 09:00 UTC is 11:00 in the zone of from
+			name:      "Within Zone Of From",
+			from:      time.Date(2021, 7, 18, 0, 0, 0, 0, berlin),
+			startTime: time.Date(2021, 7, 18, 9, 0, 0, 0, time.UTC),
+			want:      true,
+		},
+		{
+			// 10:30 UTC is 12:30 in the zone of from
+			name:      "After Zone Of From",
+			from:      time.Date(2021, 7, 18, 0, 0, 0, 0, berlin),
+			startTime: time.Date(2021, 7, 18, 10, 30, 0, 0, time.UTC),
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := SlotSearch{From: tt.from, FromMinute: 9 * 60, ToMinute: 12 * 60}
+
+			if got := search.WithinTimeOfDay(tt.startTime); got != tt.want {
+				t.Errorf("WithinTimeOfDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
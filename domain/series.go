@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Series is a recurring appointment of a Patient with a Doctor, every
+// IntervalDays days.
+type Series struct {
+	ID           int          `json:"seriesid"`
+	DoctorID     int          `json:"doctorid"`
+	PatientID    int          `json:"patientid"`
+	IntervalDays int          `json:"intervaldays"`
+	Occurrences  []Occurrence `json:"occurrences"`
+}
+
+// Occurrence is one appointment of a series. Error tells why it cannot be
+// booked.
+type Occurrence struct {
+	StartTime     time.Time `json:"starttime"`
+	AppointmentID int       `json:"appointmentid,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Conflicts counts the occurrences that cannot be booked.
+func (s Series) Conflicts() int {
+	var count int
+
+	for _, occurrence := range s.Occurrences {
+		if len(occurrence.Error) != 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// SeriesRequest asks for Occurrences appointments with the Doctor named
+// DoctorName every IntervalDays days from StartTime. DryRun only checks them.
+type SeriesRequest struct {
+	DoctorName   string
+	DependentID  int
+	StartTime    time.Time
+	IntervalDays int
+	Occurrences  int
+	Reason       string
+	DryRun       bool
+}
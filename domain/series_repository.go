@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"appointment/errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// BookSeries books every occurrence of the series within one transaction, so
+// either all or none of them are booked. bookedBy is the account booking it.
+func (ar *apptRepo) BookSeries(series Series, bookedBy int, reason string) (Series, errors.AppointmentErr) {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return series, errors.NewInternalServerError("error occured when starting transaction for booking series", err)
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO appointment_series(doctor_id, patient_id, booked_by, interval_days) VALUES (?, ?, ?, ?);"
+
+	result, err := tx.Exec(query, series.DoctorID, series.PatientID, bookedBy, series.IntervalDays)
+	if err != nil {
+		return series, errors.NewInternalServerError("error occured when executing statement for booking series", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return series, errors.NewInternalServerError("error occured when getting series ID", err)
+	}
+
+	for i, occurrence := range series.Occurrences {
+		appointmentID, appErr := insertAppointment(tx, series.DoctorID, series.PatientID, bookedBy, occurrence.StartTime, reason, int(id), 0, "")
+		if appErr != nil {
+			if appErr.GetStatus() == http.StatusConflict {
+				return series, errors.NewConflictError(fmt.Sprintf("Slot at %s already taken", occurrence.StartTime.UTC().Format(time.RFC3339)), nil)
+			}
+
+			return series, appErr
+		}
+
+		series.Occurrences[i].AppointmentID = appointmentID
+	}
+
+	if err = tx.Commit(); err != nil {
+		return series, errors.NewInternalServerError("error occured when committing booked series", err)
+	}
+
+	series.ID = int(id)
+
+	return series, nil
+}
+
+// GetSeriesBookings returns the occurrences of the series starting at or after
+// from that have not taken place or been cancelled yet.
+func (ar *apptRepo) GetSeriesBookings(seriesID int, from time.Time) ([]Booking, errors.AppointmentErr) {
+	bookings := make([]Booking, 0)
+
+	query := "SELECT id, doctor_id, patient_id, COALESCE(booked_by, patient_id), start_time, duration_minutes, reason, status FROM appointments WHERE series_id=? AND start_time>=? AND status IN (?, ?) ORDER BY start_time;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when preparing statement to fetch series appointments", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(seriesID, from.UTC(), StatusRequested, StatusConfirmed)
+	if err != nil {
+		return bookings, errors.NewInternalServerError("error occured when executing statement to fetch series appointments", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		booking := Booking{Active: true, SeriesID: seriesID}
+
+		if err := rows.Scan(&booking.ID, &booking.DoctorID, &booking.PatientID, &booking.BookedBy, &booking.StartTime, &booking.DurationMinutes, &booking.Reason, &booking.Status); err != nil {
+			return bookings, errors.NewInternalServerError("error occured when parsing series appointments", err)
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// CancelAppointments cancels the bookings within one transaction, so either
+// all or none of them are cancelled. late flags the late cancellations at the
+// same index.
+func (ar *apptRepo) CancelAppointments(bookings []Booking, late []bool, userID int, userType string, reason string) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to cancel appointments", err)
+	}
+	defer tx.Rollback()
+
+	for i, booking := range bookings {
+		if appErr := cancelAppointment(tx, booking, userID, userType, reason, late[i]); appErr != nil {
+			return appErr
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing cancelled appointments", err)
+	}
+
+	return nil
+}
+
+// RescheduleAppointments moves each appointment to the start time at the same
+// index within one transaction, so either all or none of them are moved.
+func (ar *apptRepo) RescheduleAppointments(appointmentIDs []int, doctorID int, startTimes []time.Time, actorID int, actorType string) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to reschedule appointments", err)
+	}
+	defer tx.Rollback()
+
+	for i, appointmentID := range appointmentIDs {
+		if appErr := rescheduleAppointment(tx, appointmentID, doctorID, startTimes[i], actorID, actorType, time.Time{}); appErr != nil {
+			return appErr
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing rescheduled appointments", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package domain
+
+// DoctorSettings holds the booking rules of a Doctor. A zero MaxAdvanceDays
+// places no limit on how far ahead patients can book. Cancellations less than
+// CancellationCutoffMinutes before the appointment are late. When
+// ApprovalRequired, Patients book pending requests the Doctor has
+// RequestExpiryHours to approve.
+type DoctorSettings struct {
+	DoctorID                  int  `json:"doctorid"`
+	MinNoticeMinutes          int  `json:"minnoticeminutes"`
+	MaxAdvanceDays            int  `json:"maxadvancedays"`
+	CancellationCutoffMinutes int  `json:"cancellationcutoffminutes"`
+	RequireCancellationReason bool `json:"requirecancellationreason"`
+	ApprovalRequired          bool `json:"approvalrequired"`
+	RequestExpiryHours        int  `json:"requestexpiryhours"`
+}
+
+// DefaultRequestExpiryHours is how long booking requests wait for approval
+// unless the Doctor sets otherwise.
+const DefaultRequestExpiryHours = 24
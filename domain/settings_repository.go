@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"appointment/errors"
+	"database/sql"
+)
+
+// GetDoctorSettings returns the settings of the Doctor, or the defaults if
+// the Doctor has not saved any.
+func (ar *apptRepo) GetDoctorSettings(doctorID int) (DoctorSettings, errors.AppointmentErr) {
+	settings := DoctorSettings{DoctorID: doctorID, RequestExpiryHours: DefaultRequestExpiryHours}
+
+	query := "SELECT min_notice_minutes, max_advance_days, cancellation_cutoff_minutes, require_cancellation_reason, approval_required, request_expiry_hours FROM doctor_settings WHERE doctor_id=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return settings, errors.NewInternalServerError("error occured when preparing statement to fetch Doctor settings", err)
+	}
+	defer stmt.Close()
+
+	result := stmt.QueryRow(doctorID)
+
+	err = result.Scan(&settings.MinNoticeMinutes, &settings.MaxAdvanceDays, &settings.CancellationCutoffMinutes, &settings.RequireCancellationReason, &settings.ApprovalRequired, &settings.RequestExpiryHours)
+	if err != nil && err != sql.ErrNoRows {
+		return settings, errors.NewInternalServerError("error occured when executing statement to fetch Doctor settings", err)
+	}
+
+	return settings, nil
+}
+
+func (ar *apptRepo) SaveDoctorSettings(settings DoctorSettings) errors.AppointmentErr {
+	query := "INSERT INTO doctor_settings(doctor_id, min_notice_minutes, max_advance_days, cancellation_cutoff_minutes, require_cancellation_reason, approval_required, request_expiry_hours) VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT(doctor_id) DO UPDATE SET min_notice_minutes=excluded.min_notice_minutes, max_advance_days=excluded.max_advance_days, cancellation_cutoff_minutes=excluded.cancellation_cutoff_minutes, require_cancellation_reason=excluded.require_cancellation_reason, approval_required=excluded.approval_required, request_expiry_hours=excluded.request_expiry_hours, updated_at=CURRENT_TIMESTAMP;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to save Doctor settings", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(settings.DoctorID, settings.MinNoticeMinutes, settings.MaxAdvanceDays, settings.CancellationCutoffMinutes, settings.RequireCancellationReason, settings.ApprovalRequired, settings.RequestExpiryHours)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to save Doctor settings", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+const (
+	StatusRequested  = "requested"
+	StatusConfirmed  = "confirmed"
+	StatusCheckedIn  = "checked-in"
+	StatusInProgress = "in-progress"
+	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+	StatusNoShow     = "no-show"
+)
+
+// statusTransitions lists the statuses an appointment can move to from each
+// status. Completed, cancelled and no-show appointments are final.
+var statusTransitions = map[string][]string{
+	StatusRequested:  {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:  {StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:  {StatusInProgress, StatusCancelled},
+	StatusInProgress: {StatusCompleted},
+}
+
+// statusColumns are the appointments columns recording when each status was
+// entered. Cancellations keep using deleted_at.
+var statusColumns = map[string]string{
+	StatusConfirmed:  "confirmed_at",
+	StatusCheckedIn:  "checked_in_at",
+	StatusInProgress: "started_at",
+	StatusCompleted:  "completed_at",
+	StatusCancelled:  "deleted_at",
+	StatusNoShow:     "no_show_at",
+}
+
+// IsStatus checks if status is a known appointment status.
+func IsStatus(status string) bool {
+	if status == StatusRequested {
+		return true
+	}
+
+	_, ok := statusColumns[status]
+
+	return ok
+}
+
+// CanTransition checks if an appointment can move from one status to another.
+func CanTransition(from string, to string) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"appointment/errors"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UpdateStatus moves the appointment from status from to status to and
+// records the change in its history. If the status was changed meanwhile it
+// results in a Conflict error.
+func (ar *apptRepo) UpdateStatus(appointmentID int, from string, to string, actorID int, actorType string) errors.AppointmentErr {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when starting transaction to update appointment status", err)
+	}
+	defer tx.Rollback()
+
+	if appErr := updateStatus(tx, appointmentID, from, to, actorID, actorType); appErr != nil {
+		return appErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("error occured when committing appointment status", err)
+	}
+
+	return nil
+}
+
+// updateStatus moves the appointment to status to within the transaction.
+// Cancelled appointments free their seat.
+func updateStatus(tx *sql.Tx, appointmentID int, from string, to string, actorID int, actorType string) errors.AppointmentErr {
+	column, ok := statusColumns[to]
+	if !ok {
+		return errors.NewGeneralError(fmt.Sprintf("unknown status %s", to), nil)
+	}
+
+	active := 1
+	if to == StatusCancelled {
+		active = 0
+	}
+
+	query := "UPDATE appointments SET status=?, " + column + "=?, is_active=? WHERE id=? AND status=?;"
+
+	result, err := tx.Exec(query, to, time.Now().UTC(), active, appointmentID, from)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to update appointment status", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when getting updated appointment", err)
+	}
+
+	if rows == 0 {
+		return errors.NewConflictError(fmt.Sprintf("appointment id %d is no longer %s", appointmentID, from), nil)
+	}
+
+	query = "INSERT INTO appointment_history(appointment_id, event, doctor_id, start_time, actor_id, actor_type) SELECT id, ?, doctor_id, start_time, ?, ? FROM appointments WHERE id=?;"
+
+	if _, err := tx.Exec(query, to, actorID, actorType, appointmentID); err != nil {
+		return errors.NewInternalServerError("error occured when recording appointment history", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{
+			name: "Confirm Request",
+			from: StatusRequested,
+			to:   StatusConfirmed,
+			want: true,
+		},
+		{
+			name: "Check In",
+			from: StatusConfirmed,
+			to:   StatusCheckedIn,
+			want: true,
+		},
+		{
+			name: "Skip Check In",
+			from: StatusConfirmed,
+			to:   StatusInProgress,
+			want: false,
+		},
+		{
+			name: "No Show After Check In",
+			from: StatusCheckedIn,
+			to:   StatusNoShow,
+			want: false,
+		},
+		{
+			name: "Cancel Completed",
+			from: StatusCompleted,
+			to:   StatusCancelled,
+			want: false,
+		},
+		{
+			name: "Reopen Cancelled",
+			from: StatusCancelled,
+			to:   StatusConfirmed,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
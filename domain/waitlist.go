@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistBooked  = "booked"
+	WaitlistRemoved = "removed"
+	WaitlistExpired = "expired"
+)
+
+// WaitlistEntry is a Patient waiting for a slot. Entries without a StartTime
+// wait for any slot of the Doctor on Date. Position is only set while the
+// entry is waiting.
+type WaitlistEntry struct {
+	ID            int        `json:"waitlistid"`
+	DoctorID      int        `json:"doctorid"`
+	PatientID     int        `json:"patientid"`
+	StartTime     *time.Time `json:"starttime,omitempty"`
+	Date          string     `json:"date"`
+	AutoBook      bool       `json:"autobook"`
+	Status        string     `json:"status"`
+	AppointmentID int        `json:"appointmentid,omitempty"`
+	Position      int        `json:"position,omitempty"`
+}
@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"appointment/errors"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+func (ar *apptRepo) AddToWaitlist(entry WaitlistEntry) (int, errors.AppointmentErr) {
+	var id int
+
+	var startTime sql.NullTime
+	if entry.StartTime != nil {
+		startTime = sql.NullTime{Time: *entry.StartTime, Valid: true}
+	}
+
+	query := "SELECT COUNT(id) FROM waitlist WHERE doctor_id=? AND patient_id=? AND waitlist_date=? AND status='waiting' AND ((start_time IS NULL AND ? IS NULL) OR start_time=?);"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to check for existing waitlist entry", err)
+	}
+	defer stmt.Close()
+
+	var count int
+
+	result := stmt.QueryRow(entry.DoctorID, entry.PatientID, entry.Date, startTime, startTime)
+	if err = result.Scan(&count); err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to check for existing waitlist entry", err)
+	}
+
+	if count != 0 {
+		return id, errors.NewGeneralError("Already on waitlist", nil)
+	}
+
+	query = "INSERT INTO waitlist(doctor_id, patient_id, start_time, waitlist_date, auto_book) VALUES (?, ?, ?, ?, ?);"
+
+	stmt, err = ar.db.Prepare(query)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when preparing statement to join waitlist", err)
+	}
+	defer stmt.Close()
+
+	result2, err := stmt.Exec(entry.DoctorID, entry.PatientID, startTime, entry.Date, entry.AutoBook)
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when executing statement to join waitlist", err)
+	}
+
+	newId, err := result2.LastInsertId()
+	if err != nil {
+		return id, errors.NewInternalServerError("error occured when getting waitlist ID", err)
+	}
+
+	id = int(newId)
+
+	return id, nil
+}
+
+// GetWaitlist returns the waitlist entries of the Patient with the position
+// of the waiting ones. Entries ahead are the earlier waiting entries that
+// compete for the same slot.
+func (ar *apptRepo) GetWaitlist(patientID int) ([]WaitlistEntry, errors.AppointmentErr) {
+	entries := make([]WaitlistEntry, 0)
+
+	query := "SELECT w.id, w.doctor_id, w.patient_id, w.start_time, w.waitlist_date, w.auto_book, w.status, COALESCE(w.appointment_id, 0), CASE WHEN w.status='waiting' THEN (SELECT COUNT(o.id) FROM waitlist o WHERE o.doctor_id=w.doctor_id AND o.waitlist_date=w.waitlist_date AND o.status='waiting' AND o.id<=w.id AND (w.start_time IS NULL OR o.start_time IS NULL OR o.start_time=w.start_time)) ELSE 0 END FROM waitlist w WHERE w.patient_id=? ORDER BY w.id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when preparing statement to fetch waitlist", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(patientID)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when executing statement to fetch waitlist", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows, true)
+		if err != nil {
+			return entries, errors.NewInternalServerError("error occured when parsing waitlist", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetWaitingForSlot returns the entries waiting for the slot in the order
+// they joined the waitlist.
+func (ar *apptRepo) GetWaitingForSlot(doctorID int, startTime time.Time) ([]WaitlistEntry, errors.AppointmentErr) {
+	entries := make([]WaitlistEntry, 0)
+
+	query := "SELECT id, doctor_id, patient_id, start_time, waitlist_date, auto_book, status, COALESCE(appointment_id, 0) FROM waitlist WHERE doctor_id=? AND waitlist_date=? AND status='waiting' AND (start_time IS NULL OR start_time=?) ORDER BY id;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when preparing statement to fetch waitlist", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(doctorID, startTime.Format(DateFormat), startTime)
+	if err != nil {
+		return entries, errors.NewInternalServerError("error occured when executing statement to fetch waitlist", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows, false)
+		if err != nil {
+			return entries, errors.NewInternalServerError("error occured when parsing waitlist", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// UpdateWaitlistStatus moves the entry from status from to status to. It
+// reports false if the entry was no longer in status from.
+func (ar *apptRepo) UpdateWaitlistStatus(waitlistID int, from string, to string, appointmentID int) (bool, errors.AppointmentErr) {
+	query := "UPDATE waitlist SET status=?, appointment_id=NULLIF(?, 0) WHERE id=? AND status=?;"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return false, errors.NewInternalServerError("error occured when preparing statement to update waitlist", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(to, appointmentID, waitlistID, from)
+	if err != nil {
+		return false, errors.NewInternalServerError("error occured when executing statement to update waitlist", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.NewInternalServerError("error occured when updating waitlist", err)
+	}
+
+	return count != 0, nil
+}
+
+func (ar *apptRepo) RemoveFromWaitlist(waitlistID int, patientID int) errors.AppointmentErr {
+	query := "UPDATE waitlist SET status='removed' WHERE id=? AND patient_id=? AND status='waiting';"
+
+	stmt, err := ar.db.Prepare(query)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when preparing statement to leave waitlist", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(waitlistID, patientID)
+	if err != nil {
+		return errors.NewInternalServerError("error occured when executing statement to leave waitlist", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalServerError("error occured when leaving waitlist", err)
+	}
+
+	if count == 0 {
+		return errors.NewGeneralError(fmt.Sprintf("waitlist id %d is not waiting", waitlistID), nil)
+	}
+
+	return nil
+}
+
+func scanWaitlistEntry(rows *sql.Rows, withPosition bool) (WaitlistEntry, error) {
+	var entry WaitlistEntry
+	var startTime sql.NullTime
+
+	dest := []interface{}{&entry.ID, &entry.DoctorID, &entry.PatientID, &startTime, &entry.Date, &entry.AutoBook, &entry.Status, &entry.AppointmentID}
+	if withPosition {
+		dest = append(dest, &entry.Position)
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return entry, err
+	}
+
+	if startTime.Valid {
+		entry.StartTime = &startTime.Time
+	}
+
+	return entry, nil
+}
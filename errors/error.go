@@ -74,6 +74,20 @@ func NewGeneralError(message string, err error) AppointmentErr {
 	}
 }
 
+func NewConflictError(message string, err error) AppointmentErr {
+	errMsg := ""
+
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	return &appointmentErr{
+		Message: message,
+		Status:  http.StatusConflict,
+		Error:   errMsg,
+	}
+}
+
 func NewGeneralForbiddenError(message string, err error) AppointmentErr {
 	errMsg := ""
 
@@ -87,3 +101,17 @@ func NewGeneralForbiddenError(message string, err error) AppointmentErr {
 		Error:   errMsg,
 	}
 }
+
+func NewUnauthorizedError(message string, err error) AppointmentErr {
+	errMsg := ""
+
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	return &appointmentErr{
+		Message: message,
+		Status:  http.StatusUnauthorized,
+		Error:   errMsg,
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AgendaForm asks for the agenda of the Date in "YYYY-mm-dd" format, the
+// current day by default.
+type AgendaForm struct {
+	Date  string `form:"date" json:"date"`
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+func GetAgenda(c *gin.Context) {
+	var form AgendaForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	day, err := agendaDay(form.Date)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	agenda, err := services.AppointmentService.GetAgenda(userID, userType, day)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Agenda listed", "agenda": agenda})
+}
+
+// GetDoctorAgenda is GetAgenda of the versioned API, for the Doctor of the
+// path.
+func GetDoctorAgenda(c *gin.Context) {
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	doctorID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	day, err := agendaDay(c.Query("date"))
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	agenda, err := services.AppointmentService.GetAgenda(userID, userType, day)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if agenda.DoctorID != doctorID {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, agenda)
+}
+
+func agendaDay(date string) (time.Time, errors.AppointmentErr) {
+	if len(date) == 0 {
+		return time.Now(), nil
+	}
+
+	day, err := time.Parse(domain.DateFormat, date)
+	if err != nil {
+		return day, errors.NewBadRequestError("error occured while parsing date", err)
+	}
+
+	return day, nil
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAppointmentsLimit = 20
+
+type MyAppointmentsForm struct {
+	AppointmentsQuery
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+// AppointmentsQuery filters the appointments of the Patient. When narrows
+// them down to the upcoming or past ones, the past ones being listed latest
+// first.
+type AppointmentsQuery struct {
+	DependentID int        `form:"dependentid" json:"dependentid"`
+	When        string     `form:"when" json:"when" binding:"omitempty,oneof=upcoming past"`
+	From        *time.Time `form:"from" json:"from" time_format:"2006-01-02 15:04:05"`
+	To          *time.Time `form:"to" json:"to" time_format:"2006-01-02 15:04:05"`
+	Statuses    []string   `form:"status" json:"status"`
+	Limit       int        `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int        `form:"offset" json:"offset" binding:"min=0"`
+}
+
+func MyAppointments(c *gin.Context) {
+	var form MyAppointmentsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	accountID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	filter := form.filter(accountID)
+
+	bookings, total, err := services.AppointmentService.GetAppointments(filter)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointments Listed", "appointments": bookings, "total": total, "limit": filter.Limit, "offset": filter.Offset})
+}
+
+// filter converts the query into the filter of the appointments of the
+// Patient account.
+func (q AppointmentsQuery) filter(accountID int) domain.BookingFilter {
+	filter := domain.BookingFilter{
+		AccountID: accountID,
+		PatientID: q.DependentID,
+		Statuses:  q.Statuses,
+		Limit:     q.Limit,
+		Offset:    q.Offset,
+	}
+
+	if q.From != nil {
+		filter.From = *q.From
+	}
+
+	if q.To != nil {
+		filter.To = *q.To
+	}
+
+	now := time.Now()
+
+	switch q.When {
+	case "upcoming":
+		if filter.From.Before(now) {
+			filter.From = now
+		}
+	case "past":
+		if filter.To.IsZero() || filter.To.After(now) {
+			filter.To = now
+		}
+
+		filter.Descending = true
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultAppointmentsLimit
+	}
+
+	return filter
+}
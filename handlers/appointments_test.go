@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/services"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// appointmentsResponse is the page of appointments listed.
+type appointmentsResponse struct {
+	Appointments []domain.Booking `json:"appointments"`
+	Total        int              `json:"total"`
+	Limit        int              `json:"limit"`
+	Offset       int              `json:"offset"`
+}
+
+// appointmentIDs gets the IDs of the appointments listed, in order.
+func (r appointmentsResponse) appointmentIDs() []int {
+	ids := []int{}
+	for _, booking := range r.Appointments {
+		ids = append(ids, booking.ID)
+	}
+
+	return ids
+}
+
+func TestMyAppointments(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+	startTimes := []time.Time{tomorrow.Add(-72 * time.Hour), tomorrow, tomorrow.Add(24 * time.Hour), tomorrow.Add(48 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		body       string
+		userType   string
+		wantStatus int
+		// wantIDs are the indexes of the appointments listed, the last one
+		// being cancelled
+		wantIDs   []int
+		wantTotal int
+		wantLimit int
+	}{
+		{
+			name:       "All",
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{0, 1, 2, 3},
+			wantTotal:  4,
+			wantLimit:  defaultAppointmentsLimit,
+		},
+		{
+			name:       "Upcoming",
+			body:       `"when": "upcoming"`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{1, 2, 3},
+			wantTotal:  3,
+			wantLimit:  defaultAppointmentsLimit,
+		},
+		{
+			// Past appointments are listed latest first
+			name:       "Past",
+			body:       `"when": "past"`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{0},
+			wantTotal:  1,
+			wantLimit:  defaultAppointmentsLimit,
+		},
+		{
+			// The range excludes its end
+			name:       "Date Range",
+			body:       fmt.Sprintf(`"from": %q, "to": %q`, tomorrow.Format(time.RFC3339), startTimes[3].Format(time.RFC3339)),
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{1, 2},
+			wantTotal:  2,
+			wantLimit:  defaultAppointmentsLimit,
+		},
+		{
+			name:       "Status",
+			body:       `"status": ["cancelled"]`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{3},
+			wantTotal:  1,
+			wantLimit:  defaultAppointmentsLimit,
+		},
+		{
+			name:       "Page",
+			body:       `"limit": 2, "offset": 1`,
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{1, 2},
+			wantTotal:  4,
+			wantLimit:  2,
+		},
+		{
+			name:       "Limit Too Large",
+			body:       `"limit": 101`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown When",
+			body:       `"when": "tomorrow"`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Doctor",
+			userType:   "doctor",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, patientID, appointIDs := addBookings(t, startTimes...)
+
+			if err := services.AppointmentService.Cancel(appointIDs[3], patientID, "patient", ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+			}
+
+			userID, userType := patientID, "patient"
+			if tt.userType == "doctor" {
+				userID, userType = doctorID, "doctor"
+			}
+
+			body := fmt.Sprintf(`{"token": %q}`, token(userID, userType))
+			if len(tt.body) != 0 {
+				body = fmt.Sprintf(`{"token": %q, %s}`, token(userID, userType), tt.body)
+			}
+
+			r := gin.New()
+			r.POST("/appointments", MyAppointments)
+
+			w := serve(r, http.MethodPost, "/appointments", body, nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("POST /appointments status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got appointmentsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("an error '%s' was not expected when decoding %s", err, w.Body.String())
+			}
+
+			wantIDs := []int{}
+			for _, i := range tt.wantIDs {
+				wantIDs = append(wantIDs, appointIDs[i])
+			}
+
+			if !reflect.DeepEqual(got.appointmentIDs(), wantIDs) || got.Total != tt.wantTotal || got.Limit != tt.wantLimit {
+				t.Errorf("POST /appointments = %v of %d, limit %d, want %v of %d, limit %d", got.appointmentIDs(), got.Total, got.Limit, wantIDs, tt.wantTotal, tt.wantLimit)
+			}
+		})
+	}
+}
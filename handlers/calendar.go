@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarFeedForm struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+// CalendarFeed gives the Doctor or Patient the secret URL of their calendar
+// feed.
+func CalendarFeed(c *gin.Context) {
+	calendarFeed(c, false)
+}
+
+// ResetCalendarFeed replaces the secret URL of the calendar feed, the previous
+// one stops working.
+func ResetCalendarFeed(c *gin.Context) {
+	calendarFeed(c, true)
+}
+
+func calendarFeed(c *gin.Context, reset bool) {
+	var form CalendarFeedForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	feed, err := services.AppointmentService.GetCalendarFeed(userID, userType, reset)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Calendar feed", "url": feedURL(c, feed), "createdat": feed.CreatedAt})
+}
+
+// GetCalendar serves the iCalendar feed of the secret URL. Calendar apps
+// cannot send tokens, the secret in the URL is the credential.
+func GetCalendar(c *gin.Context) {
+	secret := strings.TrimSuffix(c.Param("feed"), ".ics")
+
+	calendar, err := services.AppointmentService.GetCalendar(secret)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// feedURL gets the absolute URL of the feed as reached by the client.
+func feedURL(c *gin.Context, feed domain.CalendarFeed) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + c.Request.Host + "/calendar/" + feed.Secret + ".ics"
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancellationReportForm asks for the cancellations from the day From through
+// the day To, in "YYYY-mm-dd" format. Admins give the Doctor ID.
+type CancellationReportForm struct {
+	DoctorID int    `form:"doctorid" json:"doctorid"`
+	From     string `form:"from" json:"from"`
+	To       string `form:"to" json:"to"`
+	Token    string `form:"token" json:"token" binding:"required"`
+}
+
+func GetCancellationReport(c *gin.Context) {
+	var form CancellationReportForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	var days [2]time.Time
+
+	for i, date := range []string{form.From, form.To} {
+		if len(date) == 0 {
+			continue
+		}
+
+		day, parseErr := time.Parse(domain.DateFormat, date)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing date", parseErr))
+
+			return
+		}
+
+		days[i] = day
+	}
+
+	report, err := services.AppointmentService.GetCancellationReport(userID, userType, form.DoctorID, days[0], days[1])
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Cancellations listed", "report": report})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddDependentForm struct {
+	Name  string `form:"name" json:"name" binding:"required"`
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+type DependentsForm struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+func AddDependent(c *gin.Context) {
+	var form AddDependentForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	accountID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	dependentID, err := services.AppointmentService.AddDependent(accountID, form.Name)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Dependent added", "dependentid": dependentID})
+}
+
+func ListDependents(c *gin.Context) {
+	var form DependentsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	accountID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	dependents, err := services.AppointmentService.GetDependents(accountID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Dependents Listed", "dependents": dependents})
+}
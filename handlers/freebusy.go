@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FreeBusyForm asks for the busy time of up to 100 Doctors between From and
+// To. Times in the query string are in RFC 3339 format, like in JSON.
+type FreeBusyForm struct {
+	DoctorIDs []int     `form:"doctorids" json:"doctorids" binding:"required,min=1,max=100"`
+	From      time.Time `form:"from" json:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" json:"to" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+func FreeBusy(c *gin.Context) {
+	var form FreeBusyForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	freeBusy, err := services.AppointmentService.GetFreeBusy(form.DoctorIDs, form.From, form.To)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Free/busy listed", "doctors": freeBusy})
+}
+
+// GetFreeBusy is FreeBusy of the versioned API, with the form in the query
+// string.
+func GetFreeBusy(c *gin.Context) {
+	var form FreeBusyForm
+
+	if err := c.ShouldBindQuery(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	freeBusy, err := services.AppointmentService.GetFreeBusy(form.DoctorIDs, form.From, form.To)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"doctors": freeBusy})
+}
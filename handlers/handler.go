@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"appointment/config"
 	"appointment/domain"
 	"appointment/errors"
 	"appointment/services"
 	"appointment/utilities"
+	"crypto/subtle"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,30 +18,68 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+// maxListDays bounds the date range of the schedule listing, enough for a
+// month view.
+const maxListDays = 42
+
 type ScheduleForm struct {
-	StartTime time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
-	EndTime   time.Time `form:"endtime" json:"endtime" binding:"required,bookabledate,multipleoffifteen,gtfield=StartTime" time_format:"2006-01-02 15:04:05"`
-	Token     string    `form:"token" json:"token" binding:"required"`
+	ScheduleFields
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+type ScheduleFields struct {
+	StartTime       time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	EndTime         time.Time `form:"endtime" json:"endtime" binding:"required,bookabledate,multipleoffifteen,gtfield=StartTime" time_format:"2006-01-02 15:04:05"`
+	Capacity        int       `form:"capacity" json:"capacity" binding:"omitempty,min=1"`
+	AppointmentType string    `form:"appointmenttype" json:"appointmenttype"`
 }
 
 type BookAppointmentForm struct {
-	DoctorName string    `form:"doctorname" json:"doctorname" binding:"required"`
-	StartTime  time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
-	Token      string    `form:"token" json:"token" binding:"required"`
+	DoctorName  string    `form:"doctorname" json:"doctorname" binding:"required"`
+	StartTime   time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	DependentID int       `form:"dependentid" json:"dependentid"`
+	PatientID   int       `form:"patientid" json:"patientid"`
+	Reason      string    `form:"reason" json:"reason" binding:"max=500"`
+	Token       string    `form:"token" json:"token" binding:"required"`
 }
 
 type ListAppointmentsForm struct {
 	DoctorName string `form:"doctorname" json:"doctorname" binding:"required"`
+	ScheduleQuery
+}
+
+// ScheduleQuery selects the slots to list between the From and To dates, both
+// included. Only the current day is listed by default.
+type ScheduleQuery struct {
+	From            string `form:"from" json:"from"`
+	To              string `form:"to" json:"to"`
+	Only            string `form:"only" json:"only" binding:"omitempty,oneof=free booked"`
+	AppointmentType string `form:"appointmenttype" json:"appointmenttype"`
+	Limit           int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset          int    `form:"offset" json:"offset" binding:"min=0"`
 }
 
 type CancelAppointmentForm struct {
 	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Reason        string `form:"reason" json:"reason" binding:"max=500"`
 	Token         string `form:"token" json:"token" binding:"required"`
 }
 
+type RescheduleAppointmentForm struct {
+	AppointmentID int       `form:"appointmentid" json:"appointmentid" binding:"required"`
+	DoctorName    string    `form:"doctorname" json:"doctorname"`
+	StartTime     time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	Token         string    `form:"token" json:"token" binding:"required"`
+}
+
 type SignupForm struct {
-	Name string `form:"name" json:"name" binding:"required"`
-	Type string `form:"type" json:"usertype" binding:"required"`
+	Name      string `form:"name" json:"name" binding:"required"`
+	Type      string `form:"type" json:"usertype" binding:"required"`
+	Region    string `form:"region" json:"region"`
+	Specialty string `form:"specialty" json:"specialty"`
+	Contact   string `form:"contact" json:"contact" binding:"max=255"`
+	AdminKey  string `form:"adminkey" json:"adminkey"`
+	Token     string `form:"token" json:"token"`
 }
 
 func SetSchedule(c *gin.Context) {
@@ -77,7 +118,7 @@ func SetSchedule(c *gin.Context) {
 		return
 	}
 
-	if err := services.AppointmentService.AddSchedule(doctorID, form.StartTime, form.EndTime); err != nil {
+	if _, err := services.AppointmentService.AddSchedule(doctorID, form.StartTime, form.EndTime, form.Capacity, form.AppointmentType); err != nil {
 		c.JSON(err.GetStatus(), err)
 
 		return
@@ -102,22 +143,6 @@ func BookAppointment(c *gin.Context) {
 		return
 	}
 
-	switch strings.ToLower(userType) {
-	case "patient":
-		break
-	case "doctor":
-		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
-
-		return
-	default:
-		c.JSON(http.StatusBadRequest, errors.NewGeneralError("unknown usertype", nil))
-
-		return
-	}
-
-	var appointmentID int
-	var err2 errors.AppointmentErr
-
 	userID, err := strconv.Atoi(id)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing userID", err))
@@ -125,14 +150,38 @@ func BookAppointment(c *gin.Context) {
 		return
 	}
 
-	appointmentID, err2 = services.AppointmentService.Book(form.DoctorName, userID, form.StartTime)
+	booking, err2 := book(userID, strings.ToLower(userType), form.DoctorName, form.StartTime, form.DependentID, form.PatientID, form.Reason)
 	if err2 != nil {
 		c.JSON(err2.GetStatus(), err2)
 
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment booked", "appointmentid": appointmentID})
+	if booking.Status == domain.StatusRequested {
+		c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment requested, pending the approval of the Doctor", "appointmentid": booking.ID, "requestexpiresat": booking.RequestExpiresAt})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment booked", "appointmentid": booking.ID})
+}
+
+// book books the appointment for Patients themselves or their dependents, or
+// for the Patient patientID when booked by Doctors or their delegates.
+func book(userID int, userType string, doctorName string, startTime time.Time, dependentID int, patientID int, reason string) (domain.Booking, errors.AppointmentErr) {
+	switch userType {
+	case "patient":
+		return services.AppointmentService.Book(doctorName, userID, dependentID, startTime, reason)
+	case "doctor", "delegate":
+		// Doctors book follow-ups for their Patients
+		if patientID == 0 {
+			return domain.Booking{}, errors.NewGeneralError("patientid is required", nil)
+		}
+
+		return services.AppointmentService.BookForPatient(doctorName, userID, userType, patientID, startTime, reason)
+	}
+
+	return domain.Booking{}, errors.NewGeneralError("unknown usertype", nil)
 }
 
 func ListAppointments(c *gin.Context) {
@@ -144,16 +193,62 @@ func ListAppointments(c *gin.Context) {
 		return
 	}
 
-	var appointments []domain.Appointment
+	filter, err := form.filter()
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
 
-	appointments, err := services.AppointmentService.ListSchedule(form.DoctorName)
+	appointments, total, summary, err := services.AppointmentService.ListSchedule(form.DoctorName, filter)
 	if err != nil {
 		c.JSON(err.GetStatus(), err)
 
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointments Listed", "appointments": appointments})
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointments Listed", "appointments": appointments, "total": total, "limit": filter.Limit, "offset": filter.Offset, "summary": summary})
+}
+
+// filter converts the query into the filter of the slots to list.
+func (q ScheduleQuery) filter() (domain.ScheduleFilter, errors.AppointmentErr) {
+	filter := domain.ScheduleFilter{
+		From:            time.Now().UTC().Truncate(24 * time.Hour),
+		Only:            q.Only,
+		AppointmentType: q.AppointmentType,
+		Limit:           q.Limit,
+		Offset:          q.Offset,
+	}
+
+	if len(q.From) != 0 {
+		from, err := time.Parse(domain.DateFormat, q.From)
+		if err != nil {
+			return filter, errors.NewBadRequestError("error occured while parsing from", err)
+		}
+
+		filter.From = from
+	}
+
+	filter.To = filter.From.AddDate(0, 0, 1)
+
+	if len(q.To) != 0 {
+		to, err := time.Parse(domain.DateFormat, q.To)
+		if err != nil {
+			return filter, errors.NewBadRequestError("error occured while parsing to", err)
+		}
+
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if !filter.To.After(filter.From) {
+		return filter, errors.NewGeneralError("to cannot be before from", nil)
+	}
+
+	if filter.To.After(filter.From.AddDate(0, 0, maxListDays)) {
+		return filter, errors.NewGeneralError(fmt.Sprintf("At most %d days can be listed at once", maxListDays), nil)
+	}
+
+	return filter, nil
 }
 
 func CancelAppointment(c *gin.Context) {
@@ -181,7 +276,7 @@ func CancelAppointment(c *gin.Context) {
 
 	userType = strings.ToLower(userType)
 
-	if err := services.AppointmentService.Cancel(form.AppointmentID, userID, userType); err != nil {
+	if err := services.AppointmentService.Cancel(form.AppointmentID, userID, userType, form.Reason); err != nil {
 		c.JSON(err.GetStatus(), err)
 
 		return
@@ -190,6 +285,31 @@ func CancelAppointment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment cancelled"})
 }
 
+func RescheduleAppointment(c *gin.Context) {
+	var form RescheduleAppointmentForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if err := services.AppointmentService.Reschedule(form.AppointmentID, userID, userType, form.DoctorName, form.StartTime); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment rescheduled", "appointmentid": form.AppointmentID})
+}
+
 func Signup(c *gin.Context) {
 	var form SignupForm
 
@@ -206,7 +326,7 @@ func Signup(c *gin.Context) {
 	case "patient":
 		var err errors.AppointmentErr
 
-		userID, err = services.AppointmentService.CreatePatientAccount(form.Name)
+		userID, err = services.AppointmentService.CreatePatientAccount(form.Name, form.Contact)
 		if err != nil {
 			c.JSON(err.GetStatus(), err)
 
@@ -215,7 +335,45 @@ func Signup(c *gin.Context) {
 	case "doctor":
 		var err errors.AppointmentErr
 
-		userID, err = services.AppointmentService.CreateDoctorAccount(form.Name)
+		userID, err = services.AppointmentService.CreateDoctorAccount(domain.Doctor{Name: form.Name, Region: form.Region, Specialty: form.Specialty})
+		if err != nil {
+			c.JSON(err.GetStatus(), err)
+
+			return
+		}
+	case "admin":
+		adminKey := config.AdminKey()
+
+		if len(adminKey) == 0 || subtle.ConstantTimeCompare([]byte(adminKey), []byte(form.AdminKey)) != 1 {
+			c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+			return
+		}
+
+		var err errors.AppointmentErr
+
+		userID, err = services.AppointmentService.CreateAdminAccount(form.Name)
+		if err != nil {
+			c.JSON(err.GetStatus(), err)
+
+			return
+		}
+	case "delegate":
+		// Delegates are created by the Doctor they act for
+		doctorID, userType, err := parseUser(form.Token)
+		if err != nil {
+			c.JSON(err.GetStatus(), err)
+
+			return
+		}
+
+		if userType != "doctor" {
+			c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+			return
+		}
+
+		userID, err = services.AppointmentService.CreateDelegateAccount(doctorID, form.Name)
 		if err != nil {
 			c.JSON(err.GetStatus(), err)
 
@@ -229,11 +387,83 @@ func Signup(c *gin.Context) {
 
 	data := strings.Join([]string{strconv.Itoa(userID), form.Type}, "|")
 
-	token := utilities.GetCode(data)
+	token := utilities.NewToken(data)
 
 	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Account created", "token": token})
 }
 
+// parseUser gets the user ID and the lower cased user type from the token.
+func parseUser(token string) (int, string, errors.AppointmentErr) {
+	id, userType, err := utilities.ParseToken(token)
+	if err != nil {
+		return 0, userType, errors.NewBadRequestError("error occured while parsing token", err)
+	}
+
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, userType, errors.NewBadRequestError("error occured while parsing userID", err)
+	}
+
+	return userID, strings.ToLower(userType), nil
+}
+
+// bearerUser gets the user ID and the lower cased user type from the bearer
+// token of the Authorization header, writing the Unauthorized response if the
+// token is missing or invalid.
+func bearerUser(c *gin.Context) (int, string, bool) {
+	header := c.GetHeader("Authorization")
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	if len(token) == 0 || token == header {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("bearer token is required", nil))
+
+		return 0, "", false
+	}
+
+	userID, userType, err := parseUser(token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("invalid bearer token", nil))
+
+		return 0, "", false
+	}
+
+	return userID, userType, true
+}
+
+// paramID gets the ID from the path parameter, writing the Not Found response
+// if it is not a number.
+func paramID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusNotFound, errors.NewNotFoundError(fmt.Sprintf("%s %q does not exist", name, c.Param(name)), err))
+
+		return 0, false
+	}
+
+	return id, true
+}
+
+// patientFromToken gets the patient ID from the token, writing the error
+// response if the token does not belong to a Patient.
+func patientFromToken(c *gin.Context, token string) (int, bool) {
+	userID, userType, err := parseUser(token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return 0, false
+	}
+
+	if userType != "patient" {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return 0, false
+	}
+
+	return userID, true
+}
+
 var bookableDate validator.Func = func(fl validator.FieldLevel) bool {
 	date, ok := fl.Field().Interface().(time.Time)
 	if ok {
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/utilities"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	RegisterValidator()
+}
+
+// useTestRepo points domain.Repo at a new database for the test. Tests using
+// it cannot run in parallel.
+func useTestRepo(t *testing.T) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "appointments.db")+"?_txlock=immediate&_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening database", err)
+	}
+
+	if err := domain.AutoMigrate(db); err != nil {
+		t.Fatalf("an error '%s' was not expected when migrating database", err)
+	}
+
+	repo := domain.Repo
+	domain.Repo = domain.NewAppointmentRepository(db)
+
+	t.Cleanup(func() {
+		domain.Repo = repo
+		db.Close()
+	})
+}
+
+// addBookings books an appointment of a new Patient with a new Doctor at
+// each start time.
+func addBookings(t *testing.T, startTimes ...time.Time) (doctorID int, patientID int, appointIDs []int) {
+	t.Helper()
+
+	doctorID, err := domain.Repo.CreateDoctorAccount(domain.Doctor{Name: "Doctor1"})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating doctor", err.GetMessage())
+	}
+
+	patientID, err = domain.Repo.CreatePatientAccount("Patient1", "")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating patient", err.GetMessage())
+	}
+
+	for _, startTime := range startTimes {
+		appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTime, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		appointIDs = append(appointIDs, appointID)
+	}
+
+	return doctorID, patientID, appointIDs
+}
+
+// token returns the token of the user.
+func token(userID int, userType string) string {
+	return utilities.NewToken(fmt.Sprintf("%d|%s", userID, userType))
+}
+
+// bearer returns the Authorization header of the user.
+func bearer(userID int, userType string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token(userID, userType)}}
+}
+
+// v1Router routes the /v1 API like setupRouter, without idempotency.
+func v1Router() *gin.Engine {
+	r := gin.New()
+
+	v1 := r.Group("/v1")
+	v1.GET("/doctors/:id", GetDoctor)
+	v1.GET("/doctors/:id/slots", GetDoctorSlots)
+	v1.POST("/doctors/:id/schedules", CreateSchedule)
+	v1.GET("/doctors/:id/schedules/:sid", GetSchedule)
+	v1.PUT("/doctors/:id/schedules/:sid", UpdateSchedule)
+	v1.GET("/appointments", ListPatientAppointments)
+	v1.POST("/appointments", CreateAppointment)
+	v1.GET("/appointments/:id", GetAppointment)
+	v1.PATCH("/appointments/:id", UpdateAppointment)
+	v1.DELETE("/appointments/:id", DeleteAppointment)
+
+	return r
+}
+
+// serve sends the request with the JSON body and headers to the router.
+func serve(r http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldForm struct {
+	DoctorName string    `form:"doctorname" json:"doctorname" binding:"required"`
+	StartTime  time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	Token      string    `form:"token" json:"token" binding:"required"`
+}
+
+type HoldActionForm struct {
+	HoldID int    `form:"holdid" json:"holdid" binding:"required"`
+	Token  string `form:"token" json:"token" binding:"required"`
+}
+
+func HoldSlot(c *gin.Context) {
+	var form HoldForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	hold, err := services.AppointmentService.Hold(form.DoctorName, patientID, form.StartTime)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Slot held", "holdid": hold.ID, "expiresat": hold.ExpiresAt})
+}
+
+func ConfirmHold(c *gin.Context) {
+	var form HoldActionForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	booking, err := services.AppointmentService.ConfirmHold(form.HoldID, patientID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if booking.Status == domain.StatusRequested {
+		c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment requested, pending the approval of the Doctor", "appointmentid": booking.ID, "requestexpiresat": booking.RequestExpiresAt})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment booked", "appointmentid": booking.ID})
+}
+
+func ReleaseHold(c *gin.Context) {
+	var form HoldActionForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	if err := services.AppointmentService.ReleaseHold(form.HoldID, patientID); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Hold released"})
+}
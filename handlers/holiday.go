@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHolidaysForm struct {
+	Region string `form:"region" binding:"required"`
+	Token  string `form:"token" binding:"required"`
+}
+
+type HolidayOptInForm struct {
+	Date  string `form:"date" json:"date" binding:"required"`
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+func ImportHolidays(c *gin.Context) {
+	var form ImportHolidaysForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	_, userType, appErr := parseUser(form.Token)
+	if appErr != nil {
+		c.JSON(appErr.GetStatus(), appErr)
+
+		return
+	}
+
+	if userType != "admin" {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while reading holiday calendar", err))
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while reading holiday calendar", err))
+
+		return
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while reading holiday calendar", err))
+
+		return
+	}
+
+	count, appErr := services.AppointmentService.ImportHolidays(form.Region, fileHeader.Filename, data)
+	if appErr != nil {
+		c.JSON(appErr.GetStatus(), appErr)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Holidays imported", "count": count})
+}
+
+func HolidayOptIn(c *gin.Context) {
+	var form HolidayOptInForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	doctorID, userType, appErr := parseUser(form.Token)
+	if appErr != nil {
+		c.JSON(appErr.GetStatus(), appErr)
+
+		return
+	}
+
+	if userType != "doctor" {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return
+	}
+
+	date, err := time.Parse(domain.DateFormat, form.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing date", err))
+
+		return
+	}
+
+	if err := services.AppointmentService.OptInHoliday(doctorID, date); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Opted in to work on holiday"})
+}
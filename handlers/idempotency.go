@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header.
+const maxIdempotencyKeyLength = 255
+
+// responseRecorder keeps a copy of the response body written by a handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to retry.
+// A request repeating the key, credential and body of an earlier one gets the
+// original response replayed instead of being processed again.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if len(key) == 0 {
+			c.Next()
+
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.NewGeneralError(fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength), nil))
+
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while reading request", err))
+
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Query parameters are part of the request too
+		request := body
+		if len(c.Request.URL.RawQuery) != 0 {
+			request = append([]byte(c.Request.URL.RawQuery+"\n"), body...)
+		}
+
+		sum := sha256.Sum256(request)
+		requestHash := hex.EncodeToString(sum[:])
+		path := c.Request.Method + " " + c.Request.URL.Path
+
+		// Keys are scoped to the credential, so one user's response is never
+		// replayed to another sending the same key and body
+		if authorization := c.GetHeader("Authorization"); len(authorization) != 0 {
+			sum := sha256.Sum256([]byte(authorization))
+			key = hex.EncodeToString(sum[:16]) + ":" + key
+		}
+
+		record, reserved, appErr := services.AppointmentService.ReserveIdempotencyKey(key, path, requestHash)
+		if appErr != nil {
+			c.AbortWithStatusJSON(appErr.GetStatus(), appErr)
+
+			return
+		}
+
+		if !reserved {
+			switch {
+			case record.RequestHash != requestHash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errors.NewUnprocessibleEntityError("Idempotency-Key was already used for a different request", fmt.Errorf("request body does not match")))
+			case record.StatusCode == 0:
+				c.AbortWithStatusJSON(http.StatusConflict, errors.NewConflictError("A request with this Idempotency-Key is still being processed", nil))
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				if len(record.Location) != 0 {
+					c.Header("Location", record.Location)
+				}
+
+				c.Data(record.StatusCode, "application/json; charset=utf-8", record.Response)
+				c.Abort()
+			}
+
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Free the key if the handler panics
+		done := false
+		defer func() {
+			if !done {
+				services.AppointmentService.SaveIdempotentResponse(key, path, http.StatusInternalServerError, "", nil)
+			}
+		}()
+
+		c.Next()
+
+		done = true
+
+		if appErr := services.AppointmentService.SaveIdempotentResponse(key, path, recorder.Status(), recorder.Header().Get("Location"), recorder.body.Bytes()); appErr != nil {
+			log.Printf("%s: %s\n", appErr.GetMessage(), appErr.GetError())
+		}
+	}
+}
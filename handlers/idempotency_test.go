@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotentRouter counts the requests reaching its handlers, which answer
+// with the status in the X-Status header.
+func idempotentRouter(calls *int) *gin.Engine {
+	r := gin.New()
+
+	handler := func(c *gin.Context) {
+		*calls++
+
+		status, _ := strconv.Atoi(c.GetHeader("X-Status"))
+		if status == http.StatusCreated {
+			c.Header("Location", "/things/"+strconv.Itoa(*calls))
+		}
+
+		c.JSON(status, gin.H{"status": status, "call": *calls})
+	}
+
+	r.POST("/things", Idempotency(), handler)
+	r.POST("/others", Idempotency(), handler)
+	r.DELETE("/things", Idempotency(), handler)
+
+	return r
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		method        string
+		path          string
+		authorization string
+		body          string
+		status        int
+		wantStatus    int
+		wantReplayed  bool
+	}
+
+	first := request{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusCreated, wantStatus: http.StatusCreated}
+
+	tests := []struct {
+		name      string
+		requests  []request
+		wantCalls int
+	}{
+		{
+			name: "Replay",
+			requests: []request{
+				first,
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusCreated, wantStatus: http.StatusCreated, wantReplayed: true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Different Body",
+			requests: []request{
+				first,
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":2}`, status: http.StatusCreated, wantStatus: http.StatusUnprocessableEntity},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Server Error Released",
+			requests: []request{
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusInternalServerError, wantStatus: http.StatusInternalServerError},
+				first,
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "Unauthorized Released",
+			requests: []request{
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusUnauthorized, wantStatus: http.StatusUnauthorized},
+				first,
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "Client Error Replayed",
+			requests: []request{
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusBadRequest, wantStatus: http.StatusBadRequest},
+				{method: http.MethodPost, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusCreated, wantStatus: http.StatusBadRequest, wantReplayed: true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Scoped To Authorization",
+			requests: []request{
+				first,
+				{method: http.MethodPost, path: "/things", authorization: "Bearer b", body: `{"a":1}`, status: http.StatusCreated, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "Scoped To Path",
+			requests: []request{
+				first,
+				{method: http.MethodPost, path: "/others", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusCreated, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "Scoped To Method",
+			requests: []request{
+				first,
+				{method: http.MethodDelete, path: "/things", authorization: "Bearer a", body: `{"a":1}`, status: http.StatusNoContent, wantStatus: http.StatusNoContent},
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			var calls int
+			r := idempotentRouter(&calls)
+
+			var firstBody, firstLocation string
+
+			for i, req := range tt.requests {
+				header := http.Header{"Idempotency-Key": {"key-1"}, "X-Status": {strconv.Itoa(req.status)}}
+				if len(req.authorization) != 0 {
+					header.Set("Authorization", req.authorization)
+				}
+
+				w := serve(r, req.method, req.path, req.body, header)
+
+				if w.Code != req.wantStatus {
+					t.Fatalf("request %d status = %d, want %d: %s", i, w.Code, req.wantStatus, w.Body.String())
+				}
+
+				if replayed := w.Header().Get("Idempotent-Replayed") == "true"; replayed != req.wantReplayed {
+					t.Errorf("request %d replayed = %v, want %v", i, replayed, req.wantReplayed)
+				}
+
+				if i == 0 {
+					firstBody, firstLocation = w.Body.String(), w.Header().Get("Location")
+				} else if req.wantReplayed && (w.Body.String() != firstBody || w.Header().Get("Location") != firstLocation) {
+					t.Errorf("request %d replayed %q at %q, want %q at %q", i, w.Body.String(), w.Header().Get("Location"), firstBody, firstLocation)
+				}
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	useTestRepo(t)
+
+	started, release := make(chan bool), make(chan bool)
+
+	r := gin.New()
+	r.POST("/things", Idempotency(), func(c *gin.Context) {
+		started <- true
+		<-release
+
+		c.JSON(http.StatusCreated, gin.H{"status": http.StatusCreated})
+	})
+
+	header := http.Header{"Idempotency-Key": {"key-1"}}
+
+	var wg sync.WaitGroup
+	var firstStatus int
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		firstStatus = serve(r, http.MethodPost, "/things", `{"a":1}`, header).Code
+	}()
+
+	// The key is taken until the first request is answered
+	<-started
+
+	if w := serve(r, http.MethodPost, "/things", `{"a":1}`, header); w.Code != http.StatusConflict {
+		t.Errorf("concurrent request status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if firstStatus != http.StatusCreated {
+		t.Errorf("first request status = %d, want %d", firstStatus, http.StatusCreated)
+	}
+
+	if w := serve(r, http.MethodPost, "/things", `{"a":1}`, header); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retried request status = %d, replayed %q, want %d replayed", w.Code, w.Header().Get("Idempotent-Replayed"), http.StatusCreated)
+	}
+}
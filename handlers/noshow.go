@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NoShowsForm struct {
+	PatientID int    `form:"patientid" json:"patientid"`
+	Token     string `form:"token" json:"token" binding:"required"`
+}
+
+func GetNoShows(c *gin.Context) {
+	var form NoShowsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	record, err := services.AppointmentService.GetNoShows(form.PatientID, userID, userType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "No-shows Listed", "noshows": record})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddNoteForm struct {
+	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Note          string `form:"note" json:"note" binding:"required,max=5000"`
+	Token         string `form:"token" json:"token" binding:"required"`
+}
+
+type NotesForm struct {
+	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Token         string `form:"token" json:"token" binding:"required"`
+}
+
+func AddNote(c *gin.Context) {
+	var form AddNoteForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	noteID, err := services.AppointmentService.AddNote(form.AppointmentID, userID, userType, form.Note)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Note added", "noteid": noteID})
+}
+
+func ListNotes(c *gin.Context) {
+	var form NotesForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	notes, err := services.AppointmentService.GetNotes(form.AppointmentID, userID, userType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Notes Listed", "notes": notes})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestListNotes(t *testing.T) {
+	tests := []struct {
+		name       string
+		userType   string
+		wantStatus int
+	}{
+		{
+			name:       "Doctor",
+			userType:   "doctor",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Patient",
+			userType:   "patient",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, err := domain.Repo.CreateDoctorAccount(domain.Doctor{Name: "Doctor1"})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when creating doctor", err.GetMessage())
+			}
+
+			patientID, err := domain.Repo.CreatePatientAccount("Patient1", "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when creating patient", err.GetMessage())
+			}
+
+			appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, time.Now().Add(24*time.Hour), "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+			}
+
+			if _, err := domain.Repo.AddNote(domain.Note{AppointmentID: appointID, AuthorID: doctorID, AuthorType: "doctor", Note: "Private note"}); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding note", err.GetMessage())
+			}
+
+			userID := doctorID
+			if tt.userType == "patient" {
+				userID = patientID
+			}
+
+			r := gin.New()
+			r.POST("/notes/list", ListNotes)
+
+			w := serve(r, http.MethodPost, "/notes/list", fmt.Sprintf(`{"appointmentid": %d, "token": %q}`, appointID, token(userID, tt.userType)), nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("POST /notes/list status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if listed := strings.Contains(w.Body.String(), "Private note"); listed != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("POST /notes/list = %s, note listed %v", w.Body.String(), listed)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RequestsForm struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+type ReviewRequestForm struct {
+	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Message       string `form:"message" json:"message" binding:"max=500"`
+	Token         string `form:"token" json:"token" binding:"required"`
+}
+
+func ListRequests(c *gin.Context) {
+	var form RequestsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	requests, err := services.AppointmentService.GetRequests(userID, userType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Requests Listed", "requests": requests})
+}
+
+func ApproveRequest(c *gin.Context) {
+	reviewRequest(c, true)
+}
+
+func DeclineRequest(c *gin.Context) {
+	reviewRequest(c, false)
+}
+
+func reviewRequest(c *gin.Context, approve bool) {
+	var form ReviewRequestForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	booking, err := services.AppointmentService.ReviewRequest(form.AppointmentID, userID, userType, approve, form.Message)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	message := "Request approved"
+	if !approve {
+		message = "Request declined"
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": message, "appointment": booking})
+}
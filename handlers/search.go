@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchDays  = 14
+	defaultSearchLimit = 10
+)
+
+// SearchSlotsForm filters the slots to search for. FromTime and ToTime bound
+// the time of day in "HH:MM" format.
+type SearchSlotsForm struct {
+	Specialty       string     `form:"specialty" json:"specialty"`
+	DoctorIDs       []int      `form:"doctorids" json:"doctorids"`
+	AppointmentType string     `form:"appointmenttype" json:"appointmenttype"`
+	From            *time.Time `form:"from" json:"from" time_format:"2006-01-02 15:04:05"`
+	To              *time.Time `form:"to" json:"to" time_format:"2006-01-02 15:04:05"`
+	FromTime        string     `form:"fromtime" json:"fromtime"`
+	ToTime          string     `form:"totime" json:"totime"`
+	Limit           int        `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func SearchSlots(c *gin.Context) {
+	var form SearchSlotsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	search := domain.SlotSearch{
+		DoctorIDs:       form.DoctorIDs,
+		Specialty:       form.Specialty,
+		AppointmentType: form.AppointmentType,
+		From:            time.Now(),
+		ToMinute:        24 * 60,
+	}
+
+	if form.From != nil {
+		search.From = *form.From
+	} else if form.To != nil {
+		search.From = search.From.In(form.To.Location())
+	}
+
+	search.To = search.From.AddDate(0, 0, defaultSearchDays)
+	if form.To != nil {
+		search.To = *form.To
+	}
+
+	var err error
+
+	if len(form.FromTime) != 0 {
+		if search.FromMinute, err = parseTimeOfDay(form.FromTime); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing fromtime", err))
+
+			return
+		}
+	}
+
+	if len(form.ToTime) != 0 {
+		if search.ToMinute, err = parseTimeOfDay(form.ToTime); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing totime", err))
+
+			return
+		}
+	}
+
+	limit := form.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	slots, appErr := services.AppointmentService.SearchSlots(search, limit)
+	if appErr != nil {
+		c.JSON(appErr.GetStatus(), appErr)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Slots found", "slots": slots})
+}
+
+// parseTimeOfDay parses a "HH:MM" time into minutes after midnight.
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesForm struct {
+	DoctorName   string    `form:"doctorname" json:"doctorname" binding:"required"`
+	StartTime    time.Time `form:"starttime" json:"starttime" binding:"required,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	IntervalDays int       `form:"intervaldays" json:"intervaldays" binding:"omitempty,min=1,max=28"`
+	Occurrences  int       `form:"occurrences" json:"occurrences" binding:"required,min=2,max=52"`
+	DependentID  int       `form:"dependentid" json:"dependentid"`
+	Reason       string    `form:"reason" json:"reason" binding:"max=500"`
+	DryRun       bool      `form:"dryrun" json:"dryrun"`
+	Token        string    `form:"token" json:"token" binding:"required"`
+}
+
+type CancelSeriesForm struct {
+	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Reason        string `form:"reason" json:"reason" binding:"max=500"`
+	Token         string `form:"token" json:"token" binding:"required"`
+}
+
+func BookSeries(c *gin.Context) {
+	var form SeriesForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	request := domain.SeriesRequest{
+		DoctorName:   form.DoctorName,
+		DependentID:  form.DependentID,
+		StartTime:    form.StartTime,
+		IntervalDays: form.IntervalDays,
+		Occurrences:  form.Occurrences,
+		Reason:       form.Reason,
+		DryRun:       form.DryRun,
+	}
+
+	series, err := services.AppointmentService.BookSeries(request, userID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	switch {
+	case series.Conflicts() != 0:
+		c.JSON(http.StatusConflict, gin.H{"status": http.StatusConflict, "message": "Some occurrences cannot be booked", "series": series})
+	case form.DryRun:
+		c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "All occurrences can be booked", "series": series})
+	default:
+		c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Series booked", "series": series})
+	}
+}
+
+func CancelSeries(c *gin.Context) {
+	var form CancelSeriesForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	cancelled, err := services.AppointmentService.CancelSeries(form.AppointmentID, userID, userType, form.Reason)
+	if err != nil {
+		c.JSON(err.GetStatus(), gin.H{"status": err.GetStatus(), "message": err.GetMessage(), "error": err.GetError(), "appointmentids": cancelled})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointments cancelled", "appointmentids": cancelled})
+}
+
+func RescheduleSeries(c *gin.Context) {
+	var form RescheduleAppointmentForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	moved, err := services.AppointmentService.RescheduleSeries(form.AppointmentID, userID, userType, form.DoctorName, form.StartTime)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointments rescheduled", "appointmentids": moved})
+}
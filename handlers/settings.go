@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SettingsForm updates only the settings that are provided.
+type SettingsForm struct {
+	MinNoticeMinutes          *int   `form:"minnoticeminutes" json:"minnoticeminutes" binding:"omitempty,min=0"`
+	MaxAdvanceDays            *int   `form:"maxadvancedays" json:"maxadvancedays" binding:"omitempty,min=0"`
+	CancellationCutoffMinutes *int   `form:"cancellationcutoffminutes" json:"cancellationcutoffminutes" binding:"omitempty,min=0"`
+	RequireCancellationReason *bool  `form:"requirecancellationreason" json:"requirecancellationreason"`
+	ApprovalRequired          *bool  `form:"approvalrequired" json:"approvalrequired"`
+	RequestExpiryHours        *int   `form:"requestexpiryhours" json:"requestexpiryhours" binding:"omitempty,min=1"`
+	Token                     string `form:"token" json:"token" binding:"required"`
+}
+
+func UpdateSettings(c *gin.Context) {
+	var form SettingsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	doctorID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if userType != "doctor" {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return
+	}
+
+	settings, err := services.AppointmentService.GetDoctorSettings(doctorID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if form.MinNoticeMinutes != nil {
+		settings.MinNoticeMinutes = *form.MinNoticeMinutes
+	}
+
+	if form.MaxAdvanceDays != nil {
+		settings.MaxAdvanceDays = *form.MaxAdvanceDays
+	}
+
+	if form.CancellationCutoffMinutes != nil {
+		settings.CancellationCutoffMinutes = *form.CancellationCutoffMinutes
+	}
+
+	if form.RequireCancellationReason != nil {
+		settings.RequireCancellationReason = *form.RequireCancellationReason
+	}
+
+	if form.ApprovalRequired != nil {
+		settings.ApprovalRequired = *form.ApprovalRequired
+	}
+
+	if form.RequestExpiryHours != nil {
+		settings.RequestExpiryHours = *form.RequestExpiryHours
+	}
+
+	if err := services.AppointmentService.UpdateDoctorSettings(settings); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Settings updated", "settings": settings})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusForm struct {
+	AppointmentID int    `form:"appointmentid" json:"appointmentid" binding:"required"`
+	Status        string `form:"status" json:"status" binding:"required"`
+	Reason        string `form:"reason" json:"reason" binding:"max=500"`
+	Token         string `form:"token" json:"token" binding:"required"`
+}
+
+func UpdateStatus(c *gin.Context) {
+	var form StatusForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	userID, userType, err := parseUser(form.Token)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	booking, err := services.AppointmentService.UpdateStatus(form.AppointmentID, userID, userType, strings.ToLower(form.Status), form.Reason)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Appointment status updated", "appointment": booking})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"appointment/errors"
+	"appointment/services"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppointmentForm books an appointment like BookForm. Times are in RFC 3339
+// format, like the rest of JSON.
+type AppointmentForm struct {
+	DoctorID    int       `json:"doctorid" binding:"required"`
+	StartTime   time.Time `json:"starttime" binding:"required,bookabledate,multipleoffifteen"`
+	DependentID int       `json:"dependentid"`
+	PatientID   int       `json:"patientid"`
+	Reason      string    `json:"reason" binding:"max=500"`
+}
+
+// AppointmentPatchForm either moves the appointment to StartTime, with the
+// Doctor DoctorID or the same Doctor, or changes its Status. Times are in RFC
+// 3339 format.
+type AppointmentPatchForm struct {
+	DoctorID  int        `json:"doctorid"`
+	StartTime *time.Time `json:"starttime" binding:"omitempty,bookabledate,multipleoffifteen"`
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason" binding:"max=500"`
+}
+
+func CreateAppointment(c *gin.Context) {
+	var form AppointmentForm
+
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	doctorName, ok := doctorNameOf(c, form.DoctorID)
+	if !ok {
+		return
+	}
+
+	booking, err := book(userID, userType, doctorName, form.StartTime, form.DependentID, form.PatientID, form.Reason)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/appointments/%d", booking.ID))
+	c.JSON(http.StatusCreated, booking)
+}
+
+// ListPatientAppointments lists the appointments of the Patient like
+// MyAppointments, with the filters in the query string.
+func ListPatientAppointments(c *gin.Context) {
+	var query AppointmentsQuery
+
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	if userType != "patient" {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	filter := query.filter(userID)
+
+	bookings, total, err := services.AppointmentService.GetAppointments(filter)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointments": bookings, "total": total, "limit": filter.Limit, "offset": filter.Offset})
+}
+
+func GetAppointment(c *gin.Context) {
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	appointID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	booking, err := services.AppointmentService.GetAppointment(appointID, userID, userType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// UpdateAppointment reschedules the appointment or updates its status,
+// whichever the body asks for.
+func UpdateAppointment(c *gin.Context) {
+	var form AppointmentPatchForm
+
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	appointID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	if (form.StartTime == nil) == (len(form.Status) == 0) {
+		c.JSON(http.StatusBadRequest, errors.NewGeneralError("either starttime or status is required", nil))
+
+		return
+	}
+
+	if form.StartTime != nil {
+		var doctorName string
+
+		if form.DoctorID != 0 {
+			if doctorName, ok = doctorNameOf(c, form.DoctorID); !ok {
+				return
+			}
+		}
+
+		if err := services.AppointmentService.Reschedule(appointID, userID, userType, doctorName, *form.StartTime); err != nil {
+			c.JSON(err.GetStatus(), err)
+
+			return
+		}
+	} else {
+		if _, err := services.AppointmentService.UpdateStatus(appointID, userID, userType, strings.ToLower(form.Status), form.Reason); err != nil {
+			c.JSON(err.GetStatus(), err)
+
+			return
+		}
+	}
+
+	booking, err := services.AppointmentService.GetAppointment(appointID, userID, userType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// DeleteAppointment cancels the appointment, with the reason in the query
+// string.
+func DeleteAppointment(c *gin.Context) {
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return
+	}
+
+	appointID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	// Cancel answers Bad Request for missing appointments, like /cancel
+	if _, err := services.AppointmentService.GetAppointment(appointID, userID, userType); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	if err := services.AppointmentService.Cancel(appointID, userID, userType, c.Query("reason")); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// doctorNameOf gets the name of the Doctor referenced in the body, writing the
+// Unprocessable Entity response if they do not exist.
+func doctorNameOf(c *gin.Context, doctorID int) (string, bool) {
+	doctor, err := services.AppointmentService.GetDoctor(doctorID)
+	if err != nil {
+		if err.GetStatus() == http.StatusNotFound {
+			err = errors.NewUnprocessibleEntityError(fmt.Sprintf("Doctor %d does not exist", doctorID), fmt.Errorf("%s", err.GetError()))
+		}
+
+		c.JSON(err.GetStatus(), err)
+
+		return "", false
+	}
+
+	return doctor.Name, true
+}
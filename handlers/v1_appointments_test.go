@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/services"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateAppointment(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		doctorID   int
+		startTime  time.Time
+		wantStatus int
+	}{
+		{
+			name:       "Created",
+			startTime:  tomorrow,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "No Bearer",
+			header:     http.Header{},
+			startTime:  tomorrow,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Invalid Bearer",
+			header:     http.Header{"Authorization": {"Bearer invalid"}},
+			startTime:  tomorrow,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Unknown Doctor",
+			doctorID:   99,
+			startTime:  tomorrow,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Past",
+			startTime:  tomorrow.Add(-48 * time.Hour),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Not Scheduled",
+			startTime:  tomorrow.Add(2 * time.Hour),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, patientID, _ := addBookings(t)
+
+			if _, err := domain.Repo.AddSchedule(doctorID, tomorrow, tomorrow.Add(time.Hour), 1, ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			if tt.doctorID != 0 {
+				doctorID = tt.doctorID
+			}
+
+			header := tt.header
+			if header == nil {
+				header = bearer(patientID, "patient")
+			}
+
+			w := serve(v1Router(), http.MethodPost, "/v1/appointments", fmt.Sprintf(`{"doctorid": %d, "starttime": %q}`, doctorID, tt.startTime.Format(time.RFC3339)), header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("POST /v1/appointments status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusUnauthorized && len(w.Header().Get("WWW-Authenticate")) == 0 {
+				t.Errorf("POST /v1/appointments has no WWW-Authenticate header")
+			}
+
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var booking domain.Booking
+			if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+				t.Fatalf("an error '%s' was not expected when decoding %s", err, w.Body.String())
+			}
+
+			if location := w.Header().Get("Location"); location != fmt.Sprintf("/v1/appointments/%d", booking.ID) {
+				t.Errorf("POST /v1/appointments Location = %q, want the appointment %d", location, booking.ID)
+			}
+
+			if booking.PatientID != patientID || !booking.StartTime.Equal(tomorrow) {
+				t.Errorf("POST /v1/appointments = %+v, want the patient %d at %s", booking, patientID, tomorrow)
+			}
+		})
+	}
+}
+
+func TestAppointmentResource(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		otherUser  bool
+		wantStatus int
+	}{
+		{
+			name:       "Get",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Get Other Patient",
+			method:     http.MethodGet,
+			otherUser:  true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Get Missing",
+			method:     http.MethodGet,
+			target:     "/v1/appointments/99",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Get Not A Number",
+			method:     http.MethodGet,
+			target:     "/v1/appointments/first",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Delete",
+			method:     http.MethodDelete,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Delete Other Patient",
+			method:     http.MethodDelete,
+			otherUser:  true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Delete Missing",
+			method:     http.MethodDelete,
+			target:     "/v1/appointments/99",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			_, patientID, appointIDs := addBookings(t, time.Now().UTC().Truncate(time.Hour).Add(48*time.Hour))
+
+			userID := patientID
+			if tt.otherUser {
+				otherID, err := domain.Repo.CreatePatientAccount("Patient2", "")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating patient", err.GetMessage())
+				}
+
+				userID = otherID
+			}
+
+			target := tt.target
+			if len(target) == 0 {
+				target = fmt.Sprintf("/v1/appointments/%d", appointIDs[0])
+			}
+
+			w := serve(v1Router(), tt.method, target, "", bearer(userID, "patient"))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("%s %s status = %d, want %d: %s", tt.method, target, w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			booking, err := domain.Repo.GetBooking(appointIDs[0])
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting booking", err.GetMessage())
+			}
+
+			if cancelled := booking.Status == domain.StatusCancelled; cancelled != (tt.wantStatus == http.StatusNoContent) {
+				t.Errorf("%s %s left the appointment %s", tt.method, target, booking.Status)
+			}
+		})
+	}
+}
+
+func TestListPatientAppointments(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+	startTimes := []time.Time{tomorrow, tomorrow.Add(24 * time.Hour), tomorrow.Add(48 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		doctor     bool
+		wantStatus int
+		// wantIDs are the indexes of the appointments listed, the last one
+		// being cancelled
+		wantIDs   []int
+		wantTotal int
+	}{
+		{
+			name:       "All",
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{0, 1, 2},
+			wantTotal:  3,
+		},
+		{
+			name:       "Page",
+			query:      url.Values{"limit": {"1"}, "offset": {"1"}},
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{1},
+			wantTotal:  3,
+		},
+		{
+			name:       "Status",
+			query:      url.Values{"status": {domain.StatusCancelled}},
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{2},
+			wantTotal:  1,
+		},
+		{
+			name:       "Date Range",
+			query:      url.Values{"from": {startTimes[1].Format("2006-01-02 15:04:05")}, "to": {startTimes[2].Format("2006-01-02 15:04:05")}},
+			wantStatus: http.StatusOK,
+			wantIDs:    []int{1},
+			wantTotal:  1,
+		},
+		{
+			name:       "Invalid Date",
+			query:      url.Values{"from": {"tomorrow"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Limit Too Large",
+			query:      url.Values{"limit": {"101"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Doctor",
+			doctor:     true,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, patientID, appointIDs := addBookings(t, startTimes...)
+
+			if err := services.AppointmentService.Cancel(appointIDs[2], patientID, "patient", ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+			}
+
+			header := bearer(patientID, "patient")
+			if tt.doctor {
+				header = bearer(doctorID, "doctor")
+			}
+
+			target := "/v1/appointments?" + tt.query.Encode()
+
+			w := serve(v1Router(), http.MethodGet, target, "", header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", target, w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got appointmentsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("an error '%s' was not expected when decoding %s", err, w.Body.String())
+			}
+
+			wantIDs := []int{}
+			for _, i := range tt.wantIDs {
+				wantIDs = append(wantIDs, appointIDs[i])
+			}
+
+			if !reflect.DeepEqual(got.appointmentIDs(), wantIDs) || got.Total != tt.wantTotal {
+				t.Errorf("GET %s = %v of %d, want %v of %d", target, got.appointmentIDs(), got.Total, wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetDoctor(c *gin.Context) {
+	doctorID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	doctor, err := services.AppointmentService.GetDoctor(doctorID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, doctor)
+}
+
+// GetDoctorSlots lists the slots of the Doctor like ListAppointments, with the
+// filters in the query string.
+func GetDoctorSlots(c *gin.Context) {
+	var query ScheduleQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	doctorID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	doctor, err := services.AppointmentService.GetDoctor(doctorID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	slots, total, summary, err := services.AppointmentService.ListSchedule(doctor.Name, filter)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"slots": slots, "total": total, "limit": filter.Limit, "offset": filter.Offset, "summary": summary})
+}
+
+func CreateSchedule(c *gin.Context) {
+	var form ScheduleFields
+
+	doctorID, ok := scheduleOwner(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	scheduleID, err := services.AppointmentService.AddSchedule(doctorID, form.StartTime, form.EndTime, form.Capacity, form.AppointmentType)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	schedule, err := services.AppointmentService.GetSchedule(doctorID, scheduleID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/doctors/%d/schedules/%d", doctorID, scheduleID))
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func GetSchedule(c *gin.Context) {
+	doctorID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	scheduleID, ok := paramID(c, "sid")
+	if !ok {
+		return
+	}
+
+	schedule, err := services.AppointmentService.GetSchedule(doctorID, scheduleID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule replaces the schedule with the one in the body.
+func UpdateSchedule(c *gin.Context) {
+	var form ScheduleFields
+
+	doctorID, ok := scheduleOwner(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, ok := paramID(c, "sid")
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	schedule := domain.Schedule{
+		ID:              scheduleID,
+		DoctorID:        doctorID,
+		StartTime:       form.StartTime,
+		EndTime:         form.EndTime,
+		Capacity:        form.Capacity,
+		AppointmentType: form.AppointmentType,
+	}
+
+	if err := services.AppointmentService.UpdateSchedule(schedule); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	schedule, err := services.AppointmentService.GetSchedule(doctorID, scheduleID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// scheduleOwner gets the Doctor of the path, writing the error response unless
+// the bearer token is theirs. Only Doctors manage their schedules.
+func scheduleOwner(c *gin.Context) (int, bool) {
+	userID, userType, ok := bearerUser(c)
+	if !ok {
+		return 0, false
+	}
+
+	doctorID, ok := paramID(c, "id")
+	if !ok {
+		return 0, false
+	}
+
+	if userType != "doctor" || userID != doctorID {
+		c.JSON(http.StatusForbidden, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil))
+
+		return 0, false
+	}
+
+	return doctorID, true
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(34 * time.Hour)
+
+	tests := []struct {
+		name       string
+		userType   string
+		otherUser  bool
+		noBearer   bool
+		startTime  time.Time
+		wantStatus int
+	}{
+		{
+			name:       "Created",
+			userType:   "doctor",
+			startTime:  tomorrow.Add(2 * time.Hour),
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "Overlapping",
+			userType:   "doctor",
+			startTime:  tomorrow.Add(30 * time.Minute),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Other Doctor",
+			userType:   "doctor",
+			otherUser:  true,
+			startTime:  tomorrow.Add(2 * time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Patient",
+			userType:   "patient",
+			startTime:  tomorrow.Add(2 * time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "No Bearer",
+			noBearer:   true,
+			startTime:  tomorrow.Add(2 * time.Hour),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, patientID, _ := addBookings(t)
+
+			if _, err := domain.Repo.AddSchedule(doctorID, tomorrow, tomorrow.Add(time.Hour), 1, ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			header := http.Header{}
+			switch {
+			case tt.noBearer:
+			case tt.otherUser:
+				otherID, err := domain.Repo.CreateDoctorAccount(domain.Doctor{Name: "Doctor2"})
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating doctor", err.GetMessage())
+				}
+
+				header = bearer(otherID, tt.userType)
+			case tt.userType == "patient":
+				header = bearer(patientID, tt.userType)
+			default:
+				header = bearer(doctorID, tt.userType)
+			}
+
+			target := fmt.Sprintf("/v1/doctors/%d/schedules", doctorID)
+			body := fmt.Sprintf(`{"starttime": %q, "endtime": %q, "capacity": 2}`, tt.startTime.Format(time.RFC3339), tt.startTime.Add(time.Hour).Format(time.RFC3339))
+
+			w := serve(v1Router(), http.MethodPost, target, body, header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("POST %s status = %d, want %d: %s", target, w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var schedule domain.Schedule
+			if err := json.Unmarshal(w.Body.Bytes(), &schedule); err != nil {
+				t.Fatalf("an error '%s' was not expected when decoding %s", err, w.Body.String())
+			}
+
+			location := w.Header().Get("Location")
+			if location != fmt.Sprintf("%s/%d", target, schedule.ID) {
+				t.Fatalf("POST %s Location = %q, want the schedule %d", target, location, schedule.ID)
+			}
+
+			// The schedule created is found at its location
+			w = serve(v1Router(), http.MethodGet, location, "", nil)
+
+			var got domain.Schedule
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || w.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d %s, want 200", location, w.Code, w.Body.String())
+			}
+
+			if !got.StartTime.Equal(tt.startTime) || got.Capacity != 2 {
+				t.Errorf("GET %s = %+v, want capacity 2 at %s", location, got, tt.startTime)
+			}
+		})
+	}
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(34 * time.Hour)
+
+	tests := []struct {
+		name       string
+		target     string
+		otherUser  bool
+		startTime  time.Time
+		wantStatus int
+	}{
+		{
+			name:       "Updated",
+			startTime:  tomorrow.Add(30 * time.Minute),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Overlapping",
+			startTime:  tomorrow.Add(90 * time.Minute),
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Missing",
+			target:     "99",
+			startTime:  tomorrow.Add(30 * time.Minute),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Other Doctor",
+			otherUser:  true,
+			startTime:  tomorrow.Add(30 * time.Minute),
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, _, _ := addBookings(t)
+
+			scheduleID, err := domain.Repo.AddSchedule(doctorID, tomorrow, tomorrow.Add(time.Hour), 1, "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			if _, err := domain.Repo.AddSchedule(doctorID, tomorrow.Add(2*time.Hour), tomorrow.Add(3*time.Hour), 1, ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			header := bearer(doctorID, "doctor")
+			if tt.otherUser {
+				otherID, err := domain.Repo.CreateDoctorAccount(domain.Doctor{Name: "Doctor2"})
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating doctor", err.GetMessage())
+				}
+
+				header = bearer(otherID, "doctor")
+			}
+
+			target := fmt.Sprintf("/v1/doctors/%d/schedules/%d", doctorID, scheduleID)
+			if len(tt.target) != 0 {
+				target = fmt.Sprintf("/v1/doctors/%d/schedules/%s", doctorID, tt.target)
+			}
+
+			body := fmt.Sprintf(`{"starttime": %q, "endtime": %q}`, tt.startTime.Format(time.RFC3339), tt.startTime.Add(time.Hour).Format(time.RFC3339))
+
+			w := serve(v1Router(), http.MethodPut, target, body, header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("PUT %s status = %d, want %d: %s", target, w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			schedule, err := domain.Repo.GetSchedule(scheduleID)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting schedule", err.GetMessage())
+			}
+
+			wantStart := tomorrow
+			if tt.wantStatus == http.StatusOK {
+				wantStart = tt.startTime
+			}
+
+			if !schedule.StartTime.Equal(wantStart) {
+				t.Errorf("PUT %s left the schedule at %s, want %s", target, schedule.StartTime, wantStart)
+			}
+		})
+	}
+}
+
+func TestGetDoctorSlots(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(34 * time.Hour)
+
+	tests := []struct {
+		name       string
+		doctorID   int
+		query      url.Values
+		wantStatus int
+		wantTotal  int
+	}{
+		{
+			name:       "All",
+			query:      url.Values{"from": {tomorrow.Format(domain.DateFormat)}},
+			wantStatus: http.StatusOK,
+			wantTotal:  4,
+		},
+		{
+			name:       "Free",
+			query:      url.Values{"from": {tomorrow.Format(domain.DateFormat)}, "only": {domain.SlotsFree}},
+			wantStatus: http.StatusOK,
+			wantTotal:  3,
+		},
+		{
+			name:       "Booked",
+			query:      url.Values{"from": {tomorrow.Format(domain.DateFormat)}, "only": {domain.SlotsBooked}},
+			wantStatus: http.StatusOK,
+			wantTotal:  1,
+		},
+		{
+			// Today is listed by default
+			name:       "Other Day",
+			wantStatus: http.StatusOK,
+			wantTotal:  0,
+		},
+		{
+			name:       "Invalid Date",
+			query:      url.Values{"from": {"tomorrow"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown Doctor",
+			doctorID:   99,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID, _, _ := addBookings(t, tomorrow)
+
+			if _, err := domain.Repo.AddSchedule(doctorID, tomorrow, tomorrow.Add(time.Hour), 1, ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			if tt.doctorID != 0 {
+				doctorID = tt.doctorID
+			}
+
+			target := fmt.Sprintf("/v1/doctors/%d/slots?%s", doctorID, tt.query.Encode())
+
+			w := serve(v1Router(), http.MethodGet, target, "", nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", target, w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Total int `json:"total"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("an error '%s' was not expected when decoding %s", err, w.Body.String())
+			}
+
+			if got.Total != tt.wantTotal {
+				t.Errorf("GET %s total = %d, want %d", target, got.Total, tt.wantTotal)
+			}
+		})
+	}
+}
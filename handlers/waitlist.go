@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"appointment/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JoinWaitlistForm waits for the slot at StartTime, or for any slot on Date
+// when StartTime is not given.
+type JoinWaitlistForm struct {
+	DoctorName string     `form:"doctorname" json:"doctorname" binding:"required"`
+	StartTime  *time.Time `form:"starttime" json:"starttime" binding:"omitempty,bookabledate,multipleoffifteen" time_format:"2006-01-02 15:04:05"`
+	Date       string     `form:"date" json:"date" binding:"required_without=StartTime"`
+	AutoBook   bool       `form:"autobook" json:"autobook"`
+	Token      string     `form:"token" json:"token" binding:"required"`
+}
+
+type WaitlistForm struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+type LeaveWaitlistForm struct {
+	WaitlistID int    `form:"waitlistid" json:"waitlistid" binding:"required"`
+	Token      string `form:"token" json:"token" binding:"required"`
+}
+
+type NotificationsForm struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+func JoinWaitlist(c *gin.Context) {
+	var form JoinWaitlistForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	var date time.Time
+
+	if form.StartTime == nil {
+		var err error
+
+		date, err = time.Parse(domain.DateFormat, form.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing date", err))
+
+			return
+		}
+
+		if date.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+			c.JSON(http.StatusBadRequest, errors.NewGeneralError("date cannot be in the past", nil))
+
+			return
+		}
+	}
+
+	entry, err := services.AppointmentService.JoinWaitlist(patientID, form.DoctorName, form.StartTime, date, form.AutoBook)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Joined waitlist", "waitlist": entry})
+}
+
+func GetWaitlist(c *gin.Context) {
+	var form WaitlistForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	entries, err := services.AppointmentService.GetWaitlist(patientID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Waitlist Listed", "waitlist": entries})
+}
+
+func LeaveWaitlist(c *gin.Context) {
+	var form LeaveWaitlistForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	if err := services.AppointmentService.LeaveWaitlist(form.WaitlistID, patientID); err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Left waitlist"})
+}
+
+func GetNotifications(c *gin.Context) {
+	var form NotificationsForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("error occured while parsing input", err))
+
+		return
+	}
+
+	patientID, ok := patientFromToken(c, form.Token)
+	if !ok {
+		return
+	}
+
+	notifications, err := services.AppointmentService.GetNotifications(patientID)
+	if err != nil {
+		c.JSON(err.GetStatus(), err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK, "message": "Notifications Listed", "notifications": notifications})
+}
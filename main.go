@@ -1,9 +1,13 @@
 package main
 
 import (
+	"appointment/config"
 	"appointment/domain"
 	"appointment/handlers"
+	"appointment/services"
+	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +16,18 @@ func main() {
 	domain.Repo.InitializeDB()
 	defer domain.Repo.CloseDB()
 
+	if minutes := config.NoShowSweepMinutes(); minutes > 0 {
+		go sweepNoShows(time.Duration(minutes) * time.Minute)
+	}
+
+	if minutes := config.RequestSweepMinutes(); minutes > 0 {
+		go sweepRequests(time.Duration(minutes) * time.Minute)
+	}
+
+	if minutes := config.HoldSweepMinutes(); minutes > 0 {
+		go sweepHolds(time.Duration(minutes) * time.Minute)
+	}
+
 	r := setupRouter()
 	r.Run(port())
 }
@@ -21,11 +37,58 @@ func setupRouter() *gin.Engine {
 
 	handlers.RegisterValidator()
 
-	r.POST("/schedule", handlers.SetSchedule)
-	r.POST("/book", handlers.BookAppointment)
+	r.POST("/schedule", handlers.Idempotency(), handlers.SetSchedule)
+	r.POST("/book", handlers.Idempotency(), handlers.BookAppointment)
 	r.POST("/list", handlers.ListAppointments)
-	r.POST("/cancel", handlers.CancelAppointment)
-	r.POST("/signup", handlers.Signup)
+	r.POST("/appointments", handlers.MyAppointments)
+	r.POST("/cancel", handlers.Idempotency(), handlers.CancelAppointment)
+	r.POST("/reschedule", handlers.Idempotency(), handlers.RescheduleAppointment)
+	r.POST("/series", handlers.Idempotency(), handlers.BookSeries)
+	r.POST("/series/cancel", handlers.Idempotency(), handlers.CancelSeries)
+	r.POST("/series/reschedule", handlers.Idempotency(), handlers.RescheduleSeries)
+	r.POST("/status", handlers.Idempotency(), handlers.UpdateStatus)
+	r.POST("/requests", handlers.ListRequests)
+	r.POST("/requests/approve", handlers.Idempotency(), handlers.ApproveRequest)
+	r.POST("/requests/decline", handlers.Idempotency(), handlers.DeclineRequest)
+	r.POST("/noshows", handlers.GetNoShows)
+	r.POST("/cancellations", handlers.GetCancellationReport)
+	r.POST("/agenda", handlers.GetAgenda)
+	r.POST("/calendar", handlers.CalendarFeed)
+	r.POST("/calendar/reset", handlers.Idempotency(), handlers.ResetCalendarFeed)
+	r.GET("/calendar/:feed", handlers.GetCalendar)
+	r.POST("/waitlist", handlers.Idempotency(), handlers.JoinWaitlist)
+	r.POST("/waitlist/status", handlers.GetWaitlist)
+	r.POST("/waitlist/leave", handlers.Idempotency(), handlers.LeaveWaitlist)
+	r.POST("/notifications", handlers.GetNotifications)
+	r.POST("/hold", handlers.Idempotency(), handlers.HoldSlot)
+	r.POST("/hold/confirm", handlers.Idempotency(), handlers.ConfirmHold)
+	r.POST("/hold/release", handlers.Idempotency(), handlers.ReleaseHold)
+	r.POST("/search", handlers.SearchSlots)
+	r.POST("/freebusy", handlers.FreeBusy)
+	r.POST("/dependents", handlers.Idempotency(), handlers.AddDependent)
+	r.POST("/dependents/list", handlers.ListDependents)
+	r.POST("/notes", handlers.Idempotency(), handlers.AddNote)
+	r.POST("/notes/list", handlers.ListNotes)
+	r.POST("/signup", handlers.Idempotency(), handlers.Signup)
+	r.POST("/holidays", handlers.Idempotency(), handlers.ImportHolidays)
+	r.POST("/holidays/optin", handlers.Idempotency(), handlers.HolidayOptIn)
+	r.POST("/settings", handlers.Idempotency(), handlers.UpdateSettings)
+
+	// Resources of the versioned API, the verb routes above remain for
+	// compatibility
+	v1 := r.Group("/v1")
+	v1.GET("/doctors/:id", handlers.GetDoctor)
+	v1.GET("/doctors/:id/slots", handlers.GetDoctorSlots)
+	v1.GET("/doctors/:id/agenda", handlers.GetDoctorAgenda)
+	v1.GET("/freebusy", handlers.GetFreeBusy)
+	v1.POST("/doctors/:id/schedules", handlers.Idempotency(), handlers.CreateSchedule)
+	v1.GET("/doctors/:id/schedules/:sid", handlers.GetSchedule)
+	v1.PUT("/doctors/:id/schedules/:sid", handlers.Idempotency(), handlers.UpdateSchedule)
+	v1.GET("/appointments", handlers.ListPatientAppointments)
+	v1.POST("/appointments", handlers.Idempotency(), handlers.CreateAppointment)
+	v1.GET("/appointments/:id", handlers.GetAppointment)
+	v1.PATCH("/appointments/:id", handlers.Idempotency(), handlers.UpdateAppointment)
+	v1.DELETE("/appointments/:id", handlers.Idempotency(), handlers.DeleteAppointment)
 
 	return r
 }
@@ -41,3 +104,33 @@ func port() string {
 
 	return ":" + port
 }
+
+// sweepNoShows marks appointments nobody checked in to as no-shows at every
+// interval.
+func sweepNoShows(interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := services.AppointmentService.MarkNoShows(); err != nil {
+			log.Printf("%s: %s\n", err.GetMessage(), err.GetError())
+		}
+	}
+}
+
+// sweepRequests declines booking requests that were not approved in time at
+// every interval.
+func sweepRequests(interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := services.AppointmentService.ExpireRequests(); err != nil {
+			log.Printf("%s: %s\n", err.GetMessage(), err.GetError())
+		}
+	}
+}
+
+// sweepHolds expires slot holds that were not confirmed in time at every
+// interval, offering their slots to the waitlist again.
+func sweepHolds(interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := services.AppointmentService.ExpireHolds(); err != nil {
+			log.Printf("%s: %s\n", err.GetMessage(), err.GetError())
+		}
+	}
+}
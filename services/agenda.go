@@ -0,0 +1,96 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"sort"
+	"time"
+)
+
+// GetAgenda returns the day of the Doctor userID, or of the Doctor of the
+// delegate userID: their appointments with Patient details and notes, and the
+// gaps and blocked time of their schedule.
+func (as *appointmentService) GetAgenda(userID int, userType string, day time.Time) (domain.Agenda, errors.AppointmentErr) {
+	doctorID, err := doctorOf(userID, userType)
+	if err != nil {
+		return domain.Agenda{}, err
+	}
+
+	from := day.UTC().Truncate(24 * time.Hour)
+	to := from.AddDate(0, 0, 1)
+
+	agenda := domain.Agenda{DoctorID: doctorID, Date: from.Format(domain.DateFormat)}
+
+	appointments, err := domain.Repo.GetAgenda(doctorID, from, to)
+	if err != nil {
+		return agenda, err
+	}
+
+	slots, err := domain.Repo.ListSchedule(doctorID, from, to)
+	if err != nil {
+		return agenda, err
+	}
+
+	holidays, err := domain.Repo.GetHolidays(doctorID, from, from)
+	if err != nil {
+		return agenda, err
+	}
+
+	agenda.Entries = agendaEntries(appointments, slots, holidays)
+
+	return agenda, nil
+}
+
+// agendaEntries adds the slots without appointments to the appointments, as
+// gaps or as time blocked by holidays or holds. Consecutive slots of the same
+// kind are merged.
+func agendaEntries(appointments []domain.AgendaEntry, slots []domain.Appointment, holidays map[string]string) []domain.AgendaEntry {
+	entries := make([]domain.AgendaEntry, 0, len(appointments)+len(slots))
+	entries = append(entries, appointments...)
+
+	booked := make(map[time.Time]bool)
+	for _, appointment := range appointments {
+		booked[appointment.StartTime.UTC()] = true
+	}
+
+	var free []domain.AgendaEntry
+
+	for _, slot := range slots {
+		startTime := slot.StartTime.UTC()
+
+		if booked[startTime] {
+			continue
+		}
+
+		entry := domain.AgendaEntry{Type: domain.AgendaGap, StartTime: startTime, EndTime: startTime.Add(domain.SlotMinutes * time.Minute)}
+
+		if name, ok := holidays[startTime.Format(domain.DateFormat)]; ok {
+			entry.Type = domain.AgendaBlocked
+			entry.BlockedBy = "Holiday"
+
+			if len(name) != 0 {
+				entry.BlockedBy += ": " + name
+			}
+		} else if slot.Remaining < slot.Capacity {
+			// Seats are only taken by holds in slots without appointments
+			entry.Type = domain.AgendaBlocked
+			entry.BlockedBy = "Held for booking"
+		}
+
+		if last := len(free) - 1; last >= 0 && free[last].Type == entry.Type && free[last].BlockedBy == entry.BlockedBy && free[last].EndTime.Equal(entry.StartTime) {
+			free[last].EndTime = entry.EndTime
+
+			continue
+		}
+
+		free = append(free, entry)
+	}
+
+	entries = append(entries, free...)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartTime.Before(entries[j].StartTime)
+	})
+
+	return entries
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"appointment/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAgendaEntries(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 7, 18, hour, minute, 0, 0, time.UTC)
+	}
+
+	slot := func(hour, minute, remaining int) domain.Appointment {
+		return domain.Appointment{StartTime: at(hour, minute), Capacity: 1, Remaining: remaining}
+	}
+
+	appointment := domain.AgendaEntry{Type: domain.AgendaAppointment, StartTime: at(9, 15), EndTime: at(9, 30), AppointmentID: 1}
+	slots := []domain.Appointment{slot(9, 0, 1), slot(9, 15, 0), slot(9, 30, 1), slot(9, 45, 1), slot(10, 0, 0), slot(11, 0, 1)}
+
+	tests := []struct {
+		name     string
+		holidays map[string]string
+		want     []domain.AgendaEntry
+	}{
+		{
+			// Held slots have no appointment, schedules may not be back to back
+			name: "Gaps",
+			want: []domain.AgendaEntry{
+				{Type: domain.AgendaGap, StartTime: at(9, 0), EndTime: at(9, 15)},
+				appointment,
+				{Type: domain.AgendaGap, StartTime: at(9, 30), EndTime: at(10, 0)},
+				{Type: domain.AgendaBlocked, StartTime: at(10, 0), EndTime: at(10, 15), BlockedBy: "Held for booking"},
+				{Type: domain.AgendaGap, StartTime: at(11, 0), EndTime: at(11, 15)},
+			},
+		},
+		{
+			// Appointments booked before the holiday was loaded are kept
+			name:     "Holiday",
+			holidays: map[string]string{"2021-07-18": "Festival"},
+			want: []domain.AgendaEntry{
+				{Type: domain.AgendaBlocked, StartTime: at(9, 0), EndTime: at(9, 15), BlockedBy: "Holiday: Festival"},
+				appointment,
+				{Type: domain.AgendaBlocked, StartTime: at(9, 30), EndTime: at(10, 15), BlockedBy: "Holiday: Festival"},
+				{Type: domain.AgendaBlocked, StartTime: at(11, 0), EndTime: at(11, 15), BlockedBy: "Holiday: Festival"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agendaEntries([]domain.AgendaEntry{appointment}, slots, tt.holidays); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("agendaEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
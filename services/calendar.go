@@ -0,0 +1,242 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Calendar feeds cover the appointments of the last month and next year.
+const (
+	calendarPastDays   = 30
+	calendarFutureDays = 365
+)
+
+const calendarTimeFormat = "20060102T150405Z"
+
+// calendarEvent is a VEVENT of a calendar feed. UIDs stay the same across
+// changes so calendar apps update the event instead of adding another.
+type calendarEvent struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Status      string
+	Sequence    int
+	Transparent bool
+}
+
+// GetCalendarFeed returns the calendar feed of the Doctor or Patient,
+// creating it the first time. reset replaces its secret, e.g. after the URL
+// leaked, so the previous URL stops working.
+func (as *appointmentService) GetCalendarFeed(userID int, userType string, reset bool) (domain.CalendarFeed, errors.AppointmentErr) {
+	if userType != "doctor" && userType != "patient" {
+		return domain.CalendarFeed{}, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	if !reset {
+		feed, err := domain.Repo.GetCalendarFeed(userID, userType)
+		if err == nil {
+			return feed, nil
+		}
+
+		if err.GetStatus() != http.StatusNotFound {
+			return feed, err
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.CalendarFeed{}, errors.NewInternalServerError("error occured while generating calendar feed secret", err)
+	}
+
+	feed := domain.CalendarFeed{UserID: userID, UserType: userType, Secret: hex.EncodeToString(secret), CreatedAt: time.Now()}
+
+	if err := domain.Repo.SaveCalendarFeed(feed); err != nil {
+		return feed, err
+	}
+
+	return feed, nil
+}
+
+// GetCalendar returns the iCalendar feed with the secret. Doctors get their
+// appointments and schedules, Patients the appointments of their account and
+// dependents.
+func (as *appointmentService) GetCalendar(secret string) (string, errors.AppointmentErr) {
+	feed, err := domain.Repo.GetCalendarFeedBySecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -calendarPastDays)
+	to := now.AddDate(0, 0, calendarFutureDays)
+
+	events := make([]calendarEvent, 0)
+
+	switch feed.UserType {
+	case "doctor":
+		doctor, err := domain.Repo.GetDoctor(feed.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		schedules, err := domain.Repo.GetSchedules(doctor.ID, from, to)
+		if err != nil {
+			return "", err
+		}
+
+		for _, schedule := range schedules {
+			summary := "Available for appointments"
+			if len(schedule.AppointmentType) != 0 {
+				summary = "Available for " + schedule.AppointmentType
+			}
+
+			events = append(events, calendarEvent{
+				UID:         fmt.Sprintf("schedule-%d@appointment", schedule.ID),
+				Start:       schedule.StartTime,
+				End:         schedule.EndTime,
+				Summary:     summary,
+				Description: fmt.Sprintf("Capacity: %d per slot", schedule.Capacity),
+				Status:      "CONFIRMED",
+				Transparent: true,
+			})
+		}
+
+		bookings, err := domain.Repo.GetFeedBookings(doctor.ID, 0, from, to)
+		if err != nil {
+			return "", err
+		}
+
+		for _, booking := range bookings {
+			events = append(events, bookingEvent(booking, "Appointment with "+booking.PatientName))
+		}
+
+		return writeCalendar("Doctor "+doctor.Name+" appointments", events, now), nil
+	case "patient":
+		patient, err := domain.Repo.GetPatient(feed.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		bookings, err := domain.Repo.GetFeedBookings(0, patient.ID, from, to)
+		if err != nil {
+			return "", err
+		}
+
+		for _, booking := range bookings {
+			summary := "Appointment with Doctor " + booking.DoctorName
+			if booking.PatientID != patient.ID {
+				summary += " for " + booking.PatientName
+			}
+
+			events = append(events, bookingEvent(booking, summary))
+		}
+
+		return writeCalendar(patient.Name+" appointments", events, now), nil
+	}
+
+	return "", errors.NewNotFoundError("calendar feed does not exist", fmt.Errorf("unknown usertype %s", feed.UserType))
+}
+
+func bookingEvent(booking domain.FeedBooking, summary string) calendarEvent {
+	event := calendarEvent{
+		UID:         fmt.Sprintf("appointment-%d@appointment", booking.ID),
+		Start:       booking.StartTime,
+		End:         booking.EndTime(),
+		Summary:     summary,
+		Description: "Status: " + booking.Status,
+		Status:      "CONFIRMED",
+		Sequence:    booking.Sequence,
+	}
+
+	if len(booking.Reason) != 0 {
+		event.Description = "Reason: " + booking.Reason + "\n" + event.Description
+	}
+
+	switch booking.Status {
+	case domain.StatusRequested:
+		event.Status = "TENTATIVE"
+	case domain.StatusCancelled:
+		event.Status = "CANCELLED"
+	}
+
+	return event
+}
+
+// writeCalendar writes the events as an iCalendar (RFC 5545) calendar named
+// name, stamped now.
+func writeCalendar(name string, events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+
+	line := func(name string, value string) {
+		writeContentLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//appointment//Appointments//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeText(name))
+
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("DTSTAMP", now.UTC().Format(calendarTimeFormat))
+		line("DTSTART", event.Start.UTC().Format(calendarTimeFormat))
+		line("DTEND", event.End.UTC().Format(calendarTimeFormat))
+		line("SEQUENCE", fmt.Sprint(event.Sequence))
+		line("SUMMARY", escapeText(event.Summary))
+
+		if len(event.Description) != 0 {
+			line("DESCRIPTION", escapeText(event.Description))
+		}
+
+		line("STATUS", event.Status)
+
+		if event.Transparent {
+			line("TRANSP", "TRANSPARENT")
+		}
+
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return b.String()
+}
+
+// writeContentLine writes the line ended by CRLF, folded to lines of at most
+// 75 octets without splitting UTF-8 characters.
+func writeContentLine(b *strings.Builder, line string) {
+	limit := 75
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+
+		line = line[cut:]
+
+		// The leading space of continuation lines counts too
+		limit = 74
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 7, 18, 8, 0, 0, 0, time.UTC)
+	event := calendarEvent{
+		UID:      "appointment-1@appointment",
+		Start:    time.Date(2021, 7, 18, 9, 0, 0, 0, time.UTC),
+		End:      time.Date(2021, 7, 18, 9, 15, 0, 0, time.UTC),
+		Summary:  "Appointment with Kiran",
+		Status:   "CANCELLED",
+		Sequence: 2,
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "Escaped",
+			description: "Reason: Knee, back; neck\nStatus: cancelled",
+			want:        "DESCRIPTION:Reason: Knee\\, back\\; neck\\nStatus: cancelled\r\n",
+		},
+		{
+			// Lines are folded at 75 octets without splitting characters
+			name:        "Folded",
+			description: strings.Repeat("a", 62) + "éé",
+			want:        "DESCRIPTION:" + strings.Repeat("a", 62) + "\r\n éé\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event.Description = tt.description
+
+			got := writeCalendar("Kiran appointments", []calendarEvent{event}, now)
+
+			for _, want := range []string{"BEGIN:VCALENDAR\r\n", "UID:appointment-1@appointment\r\n", "DTSTART:20210718T090000Z\r\n", "SEQUENCE:2\r\n", "STATUS:CANCELLED\r\n", tt.want, "END:VCALENDAR\r\n"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("writeCalendar() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"time"
+)
+
+// maxReportDays is the longest period a cancellation report covers.
+const maxReportDays = 366
+
+// GetCancellationReport returns the cancellations of appointments starting
+// from the day from through the day to, by who cancelled them, with the late
+// ones listed. Doctors and their delegates get the report of the Doctor,
+// Admins that of doctorID. The period defaults to the last 30 days.
+func (as *appointmentService) GetCancellationReport(userID int, userType string, doctorID int, from time.Time, to time.Time) (domain.CancellationReport, errors.AppointmentErr) {
+	if userType == "admin" && isAdmin(userID) {
+		if doctorID == 0 {
+			return domain.CancellationReport{}, errors.NewGeneralError("Doctor ID is required", nil)
+		}
+	} else {
+		var err errors.AppointmentErr
+
+		doctorID, err = doctorOf(userID, userType)
+		if err != nil {
+			return domain.CancellationReport{}, err
+		}
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+
+	if to.Before(from) {
+		return domain.CancellationReport{}, errors.NewGeneralError("From must not be after To", nil)
+	}
+
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return domain.CancellationReport{}, errors.NewGeneralError("Report covers at most 366 days", nil)
+	}
+
+	report := domain.CancellationReport{DoctorID: doctorID, From: from.Format(domain.DateFormat), To: to.Format(domain.DateFormat)}
+
+	counts, late, err := domain.Repo.GetCancellations(doctorID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return report, err
+	}
+
+	for _, count := range counts {
+		report.Total += count.Count
+		report.LateTotal += count.Late
+	}
+
+	report.Cancellations = counts
+	report.Late = late
+
+	return report, nil
+}
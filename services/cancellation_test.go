@@ -0,0 +1,123 @@
+package services
+
+import (
+	"appointment/domain"
+	"net/http"
+	"testing"
+)
+
+func TestGetCancellationReport(t *testing.T) {
+	useTestRepo(t)
+
+	startTimes := weekly(2)
+	doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+	patientID := addPatient(t, "Patient1")
+
+	adminID, err := domain.Repo.CreateAdminAccount("Admin1")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating admin", err.GetMessage())
+	}
+
+	var appointIDs []int
+
+	for _, startTime := range startTimes {
+		appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTime, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		appointIDs = append(appointIDs, appointID)
+	}
+
+	// The Patient cancels within the cutoff, the Doctor without one
+	cancellations := []struct {
+		cutoff   int
+		userID   int
+		userType string
+	}{
+		{cutoff: 60 * 24 * 30, userID: patientID, userType: "patient"},
+		{cutoff: 0, userID: doctorID, userType: "doctor"},
+	}
+
+	for i, c := range cancellations {
+		if err := domain.Repo.SaveDoctorSettings(domain.DoctorSettings{DoctorID: doctorID, CancellationCutoffMinutes: c.cutoff, RequestExpiryHours: domain.DefaultRequestExpiryHours}); err != nil {
+			t.Fatalf("an error '%s' was not expected when saving settings", err.GetMessage())
+		}
+
+		if err := AppointmentService.Cancel(appointIDs[i], c.userID, c.userType, ""); err != nil {
+			t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		userID     int
+		userType   string
+		doctorID   int
+		wantStatus int
+	}{
+		{
+			name:     "Doctor",
+			userID:   doctorID,
+			userType: "doctor",
+		},
+		{
+			name:     "Admin",
+			userID:   adminID,
+			userType: "admin",
+			doctorID: doctorID,
+		},
+		{
+			name:       "Admin Without Doctor",
+			userID:     adminID,
+			userType:   "admin",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Patient",
+			userID:     patientID,
+			userType:   "patient",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := AppointmentService.GetCancellationReport(tt.userID, tt.userType, tt.doctorID, startTimes[0], startTimes[1])
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Fatalf("GetCancellationReport() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetCancellationReport() error = %s", err.GetMessage())
+			}
+
+			if report.Total != 2 || report.LateTotal != 1 {
+				t.Errorf("GetCancellationReport() total = %d, late = %d, want 2, 1", report.Total, report.LateTotal)
+			}
+
+			want := []domain.CancellationCount{
+				{CancelledByType: "doctor", Count: 1},
+				{CancelledByType: "patient", Count: 1, Late: 1},
+			}
+
+			if len(report.Cancellations) != len(want) {
+				t.Fatalf("GetCancellationReport() cancellations = %+v, want %+v", report.Cancellations, want)
+			}
+
+			for i := range want {
+				if report.Cancellations[i] != want[i] {
+					t.Errorf("GetCancellationReport() cancellations = %+v, want %+v", report.Cancellations, want)
+				}
+			}
+
+			if len(report.Late) != 1 || report.Late[0].ID != appointIDs[0] {
+				t.Errorf("GetCancellationReport() late = %+v, want appointment id %d", report.Late, appointIDs[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+func (as *appointmentService) AddDependent(accountID int, name string) (int, errors.AppointmentErr) {
+	account, err := domain.Repo.GetPatient(accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Dependents cannot manage dependents of their own
+	if account.AccountID != 0 {
+		return 0, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	return domain.Repo.CreateDependent(accountID, name)
+}
+
+// GetDependents returns the dependents of the Patient account with their
+// upcoming appointments.
+func (as *appointmentService) GetDependents(accountID int) ([]domain.Dependent, errors.AppointmentErr) {
+	dependents := make([]domain.Dependent, 0)
+
+	patients, err := domain.Repo.GetDependents(accountID)
+	if err != nil {
+		return dependents, err
+	}
+
+	for _, patient := range patients {
+		bookings, err := domain.Repo.GetPatientBookings(patient.ID, time.Now(), farFuture)
+		if err != nil {
+			return dependents, err
+		}
+
+		dependents = append(dependents, domain.Dependent{Patient: patient, Appointments: bookings})
+	}
+
+	return dependents, nil
+}
+
+// patientFor returns the Patient being seen when the Patient account userID
+// books for its dependent dependentID, or userID itself if no dependent is
+// given.
+func patientFor(userID int, dependentID int) (int, errors.AppointmentErr) {
+	if dependentID == 0 || dependentID == userID {
+		return userID, nil
+	}
+
+	dependent, err := domain.Repo.GetPatient(dependentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if dependent.AccountID != userID {
+		return 0, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	return dependent.ID, nil
+}
+
+// GetAppointments returns the page of appointments of the Patient account and
+// its dependents matching the filter, and how many match in total. Setting
+// PatientID narrows them down to one of its dependents.
+func (as *appointmentService) GetAppointments(filter domain.BookingFilter) ([]domain.AccountBooking, int, errors.AppointmentErr) {
+	if filter.PatientID != 0 {
+		patientID, err := patientFor(filter.AccountID, filter.PatientID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		filter.PatientID = patientID
+	}
+
+	for _, status := range filter.Statuses {
+		if !domain.IsStatus(status) {
+			return nil, 0, errors.NewGeneralError(fmt.Sprintf("Unknown status %s", status), nil)
+		}
+	}
+
+	return domain.Repo.GetAccountBookings(filter)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"appointment/domain"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDependentAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		other      bool
+		wantStatus int
+	}{
+		{
+			name: "Account Holder",
+		},
+		{
+			name:       "Other Account",
+			other:      true,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			addDoctor(t, "Doctor1", 1, startTimes...)
+			accountID := addPatient(t, "Patient1")
+
+			dependentID, err := AppointmentService.AddDependent(accountID, "Dependent1")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when adding dependent", err.GetMessage())
+			}
+
+			userID := accountID
+			if tt.other {
+				userID = addPatient(t, "Patient2")
+			}
+
+			// Book
+			booking, err := AppointmentService.Book("Doctor1", userID, dependentID, startTimes[0], "")
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Errorf("Book() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				// The account holder books, for the others to try the rest
+				if booking, err = AppointmentService.Book("Doctor1", accountID, dependentID, startTimes[0], ""); err != nil {
+					t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+				}
+			} else if err != nil {
+				t.Fatalf("Book() error = %s", err.GetMessage())
+			}
+
+			if booking.PatientID != dependentID || booking.BookedBy != accountID {
+				t.Errorf("Book() = %+v, want patient %d booked by %d", booking, dependentID, accountID)
+			}
+
+			// List
+			for _, patientID := range []int{0, dependentID} {
+				bookings, total, err := AppointmentService.GetAppointments(domain.BookingFilter{AccountID: userID, PatientID: patientID, From: time.Now(), To: farFuture, Limit: 10})
+				if tt.wantStatus != 0 && patientID != 0 {
+					if err == nil || err.GetStatus() != tt.wantStatus {
+						t.Errorf("GetAppointments() error = %v, want status %d", err, tt.wantStatus)
+					}
+
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("GetAppointments() error = %s", err.GetMessage())
+				}
+
+				want := 1
+				if tt.other {
+					want = 0
+				}
+
+				if total != want || len(bookings) != want {
+					t.Errorf("GetAppointments() of patient %d = %d appointments, want %d", patientID, total, want)
+				}
+			}
+
+			dependents, err := AppointmentService.GetDependents(userID)
+			if err != nil {
+				t.Fatalf("GetDependents() error = %s", err.GetMessage())
+			}
+
+			if tt.other && len(dependents) != 0 {
+				t.Errorf("GetDependents() of other account = %+v, want none", dependents)
+			} else if !tt.other && (len(dependents) != 1 || len(dependents[0].Appointments) != 1) {
+				t.Errorf("GetDependents() = %+v, want the dependent with its appointment", dependents)
+			}
+
+			// Cancel
+			err = AppointmentService.Cancel(booking.ID, userID, "patient", "")
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Errorf("Cancel() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Cancel() error = %s", err.GetMessage())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+// maxFreeBusyDays bounds the time range of a free/busy query.
+const maxFreeBusyDays = 42
+
+// GetFreeBusy returns the time each of the Doctors is busy with appointments
+// between from and to, in the order the Doctors are given.
+func (as *appointmentService) GetFreeBusy(doctorIDs []int, from time.Time, to time.Time) ([]domain.FreeBusy, errors.AppointmentErr) {
+	freeBusy := make([]domain.FreeBusy, 0)
+
+	if !to.After(from) {
+		return freeBusy, errors.NewGeneralError("Time range must end after it starts", nil)
+	}
+
+	if to.Sub(from) > maxFreeBusyDays*24*time.Hour {
+		return freeBusy, errors.NewGeneralError(fmt.Sprintf("Time range cannot be longer than %d days", maxFreeBusyDays), nil)
+	}
+
+	found, err := domain.Repo.GetFreeBusy(doctorIDs, from, to)
+	if err != nil {
+		return freeBusy, err
+	}
+
+	byDoctor := make(map[int]domain.FreeBusy)
+	for _, fb := range found {
+		byDoctor[fb.DoctorID] = fb
+	}
+
+	seen := make(map[int]bool)
+
+	for _, doctorID := range doctorIDs {
+		fb, ok := byDoctor[doctorID]
+		if !ok {
+			return make([]domain.FreeBusy, 0), errors.NewNotFoundError(fmt.Sprintf("Doctor %d not found in database", doctorID), fmt.Errorf("doctor does not exist"))
+		}
+
+		if !seen[doctorID] {
+			seen[doctorID] = true
+			freeBusy = append(freeBusy, fb)
+		}
+	}
+
+	return freeBusy, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"appointment/config"
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+// Hold reserves the slot for the Patient for the configured number of
+// minutes.
+func (as *appointmentService) Hold(doctorName string, patientID int, startTime time.Time) (domain.Hold, errors.AppointmentErr) {
+	var hold domain.Hold
+
+	// Get DoctorID for given DoctorName
+	doctorID, err := domain.Repo.GetDoctorID(doctorName)
+	if err != nil {
+		return hold, err
+	}
+
+	// Held slots are confirmed without approval
+	if err := checkApproval(doctorID); err != nil {
+		return hold, err
+	}
+
+	// Check If Appointment slot can be booked
+	if err := checkSlot(doctorID, startTime); err != nil {
+		return hold, err
+	}
+
+	// Check If Patient can take the Appointment
+	if err := checkPatient(patientID, doctorID, startTime); err != nil {
+		return hold, err
+	}
+
+	holdID, err := domain.Repo.HoldSlot(doctorID, patientID, startTime, holdExpiry(), 0)
+	if err != nil {
+		return hold, err
+	}
+
+	return domain.Repo.GetHold(holdID)
+}
+
+// ConfirmHold books the slot held for the Patient. Slots offered from the
+// waitlist of a Doctor who approves bookings are only requested.
+func (as *appointmentService) ConfirmHold(holdID int, patientID int) (domain.Booking, errors.AppointmentErr) {
+	hold, err := domain.Repo.GetHold(holdID)
+	if err != nil {
+		return domain.Booking{}, err
+	}
+
+	// Patient may have booked something else meanwhile
+	if hold.PatientID == patientID {
+		if err := checkPatient(patientID, hold.DoctorID, hold.StartTime); err != nil {
+			return domain.Booking{}, err
+		}
+	}
+
+	settings, err := domain.Repo.GetDoctorSettings(hold.DoctorID)
+	if err != nil {
+		return domain.Booking{}, err
+	}
+
+	var expiresAt time.Time
+	if settings.ApprovalRequired {
+		expiresAt = requestExpiry(settings, hold.StartTime, time.Now())
+	}
+
+	appointmentID, err := domain.Repo.ConfirmHold(holdID, patientID, expiresAt)
+	if err != nil {
+		return domain.Booking{ID: appointmentID}, err
+	}
+
+	if err := requireDeposit(patientID, appointmentID); err != nil {
+		return domain.Booking{ID: appointmentID}, err
+	}
+
+	return domain.Repo.GetBooking(appointmentID)
+}
+
+func (as *appointmentService) ReleaseHold(holdID int, patientID int) errors.AppointmentErr {
+	hold, err := domain.Repo.GetHold(holdID)
+	if err != nil {
+		return err
+	}
+
+	if err := domain.Repo.ReleaseHold(holdID, patientID); err != nil {
+		return err
+	}
+
+	// Offer freed slot to waitlist
+	promoteWaitlist(hold.DoctorID, hold.StartTime)
+
+	return nil
+}
+
+// ExpireHolds expires the holds nobody confirmed in time, and offers their
+// slots to the waitlist again. Waitlist entries whose offer expired move on to
+// the next Patient waiting.
+func (as *appointmentService) ExpireHolds() (int, errors.AppointmentErr) {
+	expired, err := domain.Repo.ExpireHolds(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, hold := range expired {
+		if hold.WaitlistID != 0 {
+			notify(hold.PatientID, fmt.Sprintf("The slot held for you from the waitlist at %s expired before you confirmed it.", hold.StartTime.UTC().Format(time.RFC3339)))
+		}
+
+		promoteWaitlist(hold.DoctorID, hold.StartTime)
+	}
+
+	return len(expired), nil
+}
+
+func holdExpiry() time.Time {
+	return time.Now().Add(time.Duration(config.HoldMinutes()) * time.Minute)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"appointment/domain"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExpireHolds(t *testing.T) {
+	useTestRepo(t)
+
+	startTimes := weekly(1)
+	doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+	patientID := addPatient(t, "Patient1")
+
+	appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTimes[0], "")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+	}
+
+	waiting := []int{addPatient(t, "Patient2"), addPatient(t, "Patient3")}
+
+	for _, waitingID := range waiting {
+		if _, err := AppointmentService.JoinWaitlist(waitingID, "Doctor1", &startTimes[0], startTimes[0], false); err != nil {
+			t.Fatalf("an error '%s' was not expected when joining waitlist", err.GetMessage())
+		}
+	}
+
+	// The first Patient waiting is offered a hold that expires at once
+	os.Setenv("HOLD_MINUTES", "0")
+	defer os.Unsetenv("HOLD_MINUTES")
+
+	if err := AppointmentService.Cancel(appointID, patientID, "patient", ""); err != nil {
+		t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+	}
+
+	// The next hold is offered for the default time
+	os.Unsetenv("HOLD_MINUTES")
+
+	expired, err := AppointmentService.ExpireHolds()
+	if err != nil {
+		t.Fatalf("ExpireHolds() error = %s", err.GetMessage())
+	}
+
+	if expired != 1 {
+		t.Errorf("ExpireHolds() = %d, want 1", expired)
+	}
+
+	// The offer moves on to the next Patient waiting
+	for i, want := range []string{domain.WaitlistExpired, domain.WaitlistOffered} {
+		entries, err := domain.Repo.GetWaitlist(waiting[i])
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when getting waitlist", err.GetMessage())
+		}
+
+		if len(entries) != 1 || entries[0].Status != want {
+			t.Errorf("waitlist of patient %d = %+v, want status %s", waiting[i], entries, want)
+		}
+	}
+
+	// Nothing is left to expire until the new hold runs out
+	if expired, err := AppointmentService.ExpireHolds(); err != nil || expired != 0 {
+		t.Errorf("ExpireHolds() = %d, %v, want 0", expired, err)
+	}
+}
+
+func TestConfirmHold(t *testing.T) {
+	tests := []struct {
+		name         string
+		approval     bool
+		wantStatus   string
+		wantExpiring bool
+	}{
+		{
+			name:       "No Approval",
+			wantStatus: domain.StatusConfirmed,
+		},
+		{
+			// Waiting does not skip the approval of the Doctor
+			name:         "Approval Required",
+			approval:     true,
+			wantStatus:   domain.StatusRequested,
+			wantExpiring: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			patientID := addPatient(t, "Patient1")
+			waitingID := addPatient(t, "Patient2")
+
+			appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTimes[0], "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+			}
+
+			if err := domain.Repo.SaveDoctorSettings(domain.DoctorSettings{DoctorID: doctorID, ApprovalRequired: tt.approval, RequestExpiryHours: domain.DefaultRequestExpiryHours}); err != nil {
+				t.Fatalf("an error '%s' was not expected when saving settings", err.GetMessage())
+			}
+
+			if _, err := AppointmentService.JoinWaitlist(waitingID, "Doctor1", &startTimes[0], startTimes[0], false); err != nil {
+				t.Fatalf("an error '%s' was not expected when joining waitlist", err.GetMessage())
+			}
+
+			if err := AppointmentService.Cancel(appointID, patientID, "patient", ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+			}
+
+			// The hold offered is named in the notification
+			notifications, err := domain.Repo.GetNotifications(waitingID)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting notifications", err.GetMessage())
+			}
+
+			var holdID int
+
+			for _, notification := range notifications {
+				if i := strings.LastIndex(notification.Message, "hold id "); i >= 0 {
+					fmt.Sscanf(notification.Message[i:], "hold id %d", &holdID)
+				}
+			}
+
+			if holdID == 0 {
+				t.Fatalf("no hold offered in %+v", notifications)
+			}
+
+			booking, err := AppointmentService.ConfirmHold(holdID, waitingID)
+			if err != nil {
+				t.Fatalf("ConfirmHold() error = %s", err.GetMessage())
+			}
+
+			if booking.PatientID != waitingID || booking.Status != tt.wantStatus {
+				t.Errorf("ConfirmHold() = %+v, want status %s", booking, tt.wantStatus)
+			}
+
+			if (booking.RequestExpiresAt != nil) != tt.wantExpiring {
+				t.Errorf("ConfirmHold() request expires at %v, want expiring %v", booking.RequestExpiresAt, tt.wantExpiring)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func (as *appointmentService) ImportHolidays(region string, fileName string, data []byte) (int, errors.AppointmentErr) {
+	var count int
+	var holidays []domain.Holiday
+	var err error
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ics":
+		holidays, err = parseHolidayICS(bytes.NewReader(data))
+	case ".csv":
+		holidays, err = parseHolidayCSV(bytes.NewReader(data))
+	default:
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("BEGIN:VCALENDAR")) {
+			holidays, err = parseHolidayICS(bytes.NewReader(data))
+		} else {
+			holidays, err = parseHolidayCSV(bytes.NewReader(data))
+		}
+	}
+
+	if err != nil {
+		return count, errors.NewBadRequestError("error occured while parsing holiday calendar", err)
+	}
+
+	if len(holidays) == 0 {
+		return count, errors.NewGeneralError("No holidays found in calendar", nil)
+	}
+
+	for i := range holidays {
+		holidays[i].Region = region
+	}
+
+	return domain.Repo.AddHolidays(holidays)
+}
+
+func (as *appointmentService) OptInHoliday(doctorID int, date time.Time) errors.AppointmentErr {
+	return domain.Repo.OptInHoliday(doctorID, date.Format(domain.DateFormat))
+}
+
+// checkHoliday refuses times falling on a holiday the Doctor has not opted in
+// to work on.
+func checkHoliday(doctorID int, startTime time.Time) errors.AppointmentErr {
+	holidays, err := domain.Repo.GetHolidays(doctorID, startTime, startTime)
+	if err != nil {
+		return err
+	}
+
+	if name, ok := holidays[startTime.Format(domain.DateFormat)]; ok {
+		return errors.NewGeneralError(fmt.Sprintf("Doctor is not available on public holiday %s", name), nil)
+	}
+
+	return nil
+}
+
+// parseHolidayCSV reads holidays from CSV rows of the form "date,name" where
+// date is in YYYY-MM-DD format. A header row is skipped.
+func parseHolidayCSV(r io.Reader) ([]domain.Holiday, error) {
+	holidays := make([]domain.Holiday, 0)
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return holidays, err
+		}
+
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		date, err := time.Parse(domain.DateFormat, strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+
+			return holidays, fmt.Errorf("invalid date %q on line %d", record[0], line)
+		}
+
+		holiday := domain.Holiday{Date: date.Format(domain.DateFormat)}
+		if len(record) > 1 {
+			holiday.Name = strings.TrimSpace(record[1])
+		}
+
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+// parseHolidayICS reads holidays from the VEVENT entries of an iCalendar file.
+// All day events spanning several days produce a holiday for each day.
+func parseHolidayICS(r io.Reader) ([]domain.Holiday, error) {
+	holidays := make([]domain.Holiday, 0)
+
+	lines := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// Folded lines continue the previous one
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return holidays, err
+	}
+
+	var inEvent, allDay bool
+	var name string
+	var start, end time.Time
+
+	for _, line := range lines {
+		sep := strings.Index(line, ":")
+		if sep < 0 {
+			continue
+		}
+
+		property := strings.ToUpper(line[:sep])
+		value := strings.TrimSpace(line[sep+1:])
+		params := ""
+
+		if i := strings.Index(property, ";"); i >= 0 {
+			property, params = property[:i], property[i+1:]
+		}
+
+		switch {
+		case property == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, allDay = true, false
+			name = ""
+			start, end = time.Time{}, time.Time{}
+		case !inEvent:
+			continue
+		case property == "SUMMARY":
+			name = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+		case property == "DTSTART" || property == "DTEND":
+			if len(value) < 8 {
+				return holidays, fmt.Errorf("invalid %s value %q", property, value)
+			}
+
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return holidays, fmt.Errorf("invalid %s value %q", property, value)
+			}
+
+			if property == "DTSTART" {
+				start = date
+				allDay = strings.Contains(params, "VALUE=DATE") || len(value) == 8
+			} else {
+				end = date
+			}
+		case property == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+
+			if start.IsZero() {
+				return holidays, fmt.Errorf("event %q has no DTSTART", name)
+			}
+
+			// DTEND of all day events is exclusive
+			last := start
+			if allDay && end.After(start) {
+				last = end.AddDate(0, 0, -1)
+			}
+
+			for day := start; !day.After(last); day = day.AddDate(0, 0, 1) {
+				holidays = append(holidays, domain.Holiday{Date: day.Format(domain.DateFormat), Name: name})
+			}
+		}
+	}
+
+	return holidays, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"appointment/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHolidayCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []domain.Holiday
+		wantErr bool
+	}{
+		{
+			// Header row is skipped
+			name: "OK",
+			data: "date,name\n2021-12-25,Christmas Day\n2022-01-01, New Year's Day\n",
+			want: []domain.Holiday{
+				{Date: "2021-12-25", Name: "Christmas Day"},
+				{Date: "2022-01-01", Name: "New Year's Day"},
+			},
+		},
+		{
+			// Only the first row can be a header
+			name:    "Invalid Date",
+			data:    "2021-12-25,Christmas Day\n25/12/2021,Boxing Day\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHolidayCSV(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseHolidayCSV() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHolidayCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHolidayICS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []domain.Holiday
+		wantErr bool
+	}{
+		{
+			// All day events expand to every day until the exclusive DTEND
+			name: "OK",
+			data: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20211224\r\nDTEND;VALUE=DATE:20211226\r\nSUMMARY:Christmas\r\n  Holidays\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nDTSTART:20220101T000000Z\r\nSUMMARY:New Year\\, Day\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			want: []domain.Holiday{
+				{Date: "2021-12-24", Name: "Christmas Holidays"},
+				{Date: "2021-12-25", Name: "Christmas Holidays"},
+				{Date: "2022-01-01", Name: "New Year, Day"},
+			},
+		},
+		{
+			name:    "Missing DTSTART",
+			data:    "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Christmas\nEND:VEVENT\nEND:VCALENDAR\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHolidayICS(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseHolidayICS() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHolidayICS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
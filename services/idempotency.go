@@ -0,0 +1,30 @@
+package services
+
+import (
+	"appointment/config"
+	"appointment/domain"
+	"appointment/errors"
+	"net/http"
+	"time"
+)
+
+// ReserveIdempotencyKey claims the key for a request to path with the given
+// body hash. If the key was already used within the configured window it
+// returns the stored record and false.
+func (as *appointmentService) ReserveIdempotencyKey(key string, path string, requestHash string) (domain.IdempotencyRecord, bool, errors.AppointmentErr) {
+	record := domain.IdempotencyRecord{Key: key, Path: path, RequestHash: requestHash}
+	expiry := time.Now().Add(-time.Duration(config.IdempotencyKeyHours()) * time.Hour)
+
+	return domain.Repo.ReserveIdempotencyKey(record, expiry)
+}
+
+// SaveIdempotentResponse stores the response to replay for the key. Server
+// errors and rejected credentials are not stored, so the request can be
+// retried.
+func (as *appointmentService) SaveIdempotentResponse(key string, path string, statusCode int, location string, response []byte) errors.AppointmentErr {
+	if statusCode >= 500 || statusCode == http.StatusUnauthorized {
+		return domain.Repo.ReleaseIdempotencyKey(key, path)
+	}
+
+	return domain.Repo.SaveIdempotentResponse(domain.IdempotencyRecord{Key: key, Path: path, StatusCode: statusCode, Location: location, Response: response})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"appointment/config"
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+// MarkNoShows marks appointments nobody checked in to within the grace
+// period as no-shows, and returns how many were marked.
+func (as *appointmentService) MarkNoShows() (int, errors.AppointmentErr) {
+	grace := time.Duration(config.NoShowGraceMinutes()) * time.Minute
+
+	return domain.Repo.MarkNoShows(time.Now().Add(-grace))
+}
+
+// GetNoShows returns the no-show record of the Patient to the Patient, their
+// account holder, Doctors, their delegates and Admins.
+func (as *appointmentService) GetNoShows(patientID int, userID int, userType string) (domain.NoShowRecord, errors.AppointmentErr) {
+	if userType == "patient" {
+		var err errors.AppointmentErr
+
+		patientID, err = patientFor(userID, patientID)
+		if err != nil {
+			return domain.NoShowRecord{}, err
+		}
+	} else if patientID == 0 {
+		return domain.NoShowRecord{}, errors.NewGeneralError("Patient ID is required", nil)
+	}
+
+	return noShowRecord(patientID, time.Now())
+}
+
+// noShowRecord returns the no-shows of the Patient in the current period and
+// the restrictions that apply to them.
+func noShowRecord(patientID int, now time.Time) (domain.NoShowRecord, errors.AppointmentErr) {
+	record, err := domain.Repo.GetNoShows(patientID, now.AddDate(0, 0, -config.NoShowPeriodDays()))
+	if err != nil {
+		return record, err
+	}
+
+	limit := config.NoShowLimit()
+	if limit == 0 || record.Count < limit {
+		return record, nil
+	}
+
+	if days := config.NoShowCooldownDays(); days > 0 {
+		until := record.LastNoShow.AddDate(0, 0, days)
+
+		if until.After(now) {
+			record.RestrictedUntil = &until
+		}
+	}
+
+	record.DepositRequired = config.NoShowDeposit()
+
+	return record, nil
+}
+
+// checkNoShows refuses bookings from Patients in their no-show cooldown.
+func checkNoShows(patientID int, now time.Time) errors.AppointmentErr {
+	record, err := noShowRecord(patientID, now)
+	if err != nil {
+		return err
+	}
+
+	if record.RestrictedUntil != nil {
+		return errors.NewGeneralError(fmt.Sprintf("You missed %d appointments and cannot book until %s", record.Count, record.RestrictedUntil.UTC().Format(time.RFC3339)), nil)
+	}
+
+	return nil
+}
+
+// requireDeposit flags the new appointment as requiring a deposit if the
+// Patient is restricted after missing appointments.
+func requireDeposit(patientID int, appointmentID int) errors.AppointmentErr {
+	record, err := noShowRecord(patientID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !record.DepositRequired {
+		return nil
+	}
+
+	return domain.Repo.RequireDeposit(appointmentID)
+}
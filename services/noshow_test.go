@@ -0,0 +1,276 @@
+package services
+
+import (
+	"appointment/domain"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// addNoShows books past appointments for the Patient that they missed at the
+// given times.
+func addNoShows(t *testing.T, db *sql.DB, doctorID int, patientID int, noShowAt ...time.Time) {
+	t.Helper()
+
+	for _, at := range noShowAt {
+		appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, at.Add(-time.Hour), "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		if _, err := db.Exec("UPDATE appointments SET status=?, no_show_at=? WHERE id=?;", domain.StatusNoShow, at.UTC(), appointID); err != nil {
+			t.Fatalf("an error '%s' was not expected when marking no-show", err)
+		}
+	}
+}
+
+// setNoShowPolicy restricts Patients with 2 no-shows in 30 days for 7 days
+// after the last one, and requires deposits of them, until the test ends.
+func setNoShowPolicy(t *testing.T) {
+	t.Helper()
+
+	policy := map[string]string{
+		"NO_SHOW_LIMIT":         "2",
+		"NO_SHOW_PERIOD_DAYS":   "30",
+		"NO_SHOW_COOLDOWN_DAYS": "7",
+		"NO_SHOW_DEPOSIT":       "true",
+	}
+
+	for name, value := range policy {
+		name := name
+
+		os.Setenv(name, value)
+		t.Cleanup(func() { os.Unsetenv(name) })
+	}
+}
+
+func TestMarkNoShows(t *testing.T) {
+	db := useTestRepo(t)
+
+	doctorID := addDoctor(t, "Doctor1", 1)
+	patientID := addPatient(t, "Patient1")
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	tests := []struct {
+		name       string
+		startTime  time.Time
+		status     string
+		wantStatus string
+	}{
+		{
+			name:       "Missed",
+			startTime:  now.Add(-2 * time.Hour),
+			status:     domain.StatusConfirmed,
+			wantStatus: domain.StatusNoShow,
+		},
+		{
+			name:       "Within Grace Period",
+			startTime:  now.Add(-10 * time.Minute),
+			status:     domain.StatusConfirmed,
+			wantStatus: domain.StatusConfirmed,
+		},
+		{
+			name:       "Checked In",
+			startTime:  now.Add(-3 * time.Hour),
+			status:     domain.StatusCheckedIn,
+			wantStatus: domain.StatusCheckedIn,
+		},
+		{
+			name:       "Upcoming",
+			startTime:  now.Add(2 * time.Hour),
+			status:     domain.StatusConfirmed,
+			wantStatus: domain.StatusConfirmed,
+		},
+	}
+
+	appointIDs := make([]int, len(tests))
+
+	for i, tt := range tests {
+		appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, tt.startTime, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		if _, err := db.Exec("UPDATE appointments SET status=? WHERE id=?;", tt.status, appointID); err != nil {
+			t.Fatalf("an error '%s' was not expected when setting status", err)
+		}
+
+		appointIDs[i] = appointID
+	}
+
+	marked, err := AppointmentService.MarkNoShows()
+	if err != nil {
+		t.Fatalf("MarkNoShows() error = %s", err.GetMessage())
+	}
+
+	if marked != 1 {
+		t.Errorf("MarkNoShows() = %d, want 1", marked)
+	}
+
+	for i, tt := range tests {
+		booking, err := domain.Repo.GetBooking(appointIDs[i])
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when getting booking", err.GetMessage())
+		}
+
+		if booking.Status != tt.wantStatus {
+			t.Errorf("%s appointment status = %s, want %s", tt.name, booking.Status, tt.wantStatus)
+		}
+	}
+
+	record, err := AppointmentService.GetNoShows(0, patientID, "patient")
+	if err != nil {
+		t.Fatalf("GetNoShows() error = %s", err.GetMessage())
+	}
+
+	if record.Count != 1 || record.Total != 1 {
+		t.Errorf("GetNoShows() = %+v, want 1 no-show", record)
+	}
+}
+
+func TestNoShowRestriction(t *testing.T) {
+	now := time.Now().UTC()
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+
+	tests := []struct {
+		name           string
+		noShowAt       []time.Time
+		wantCount      int
+		wantTotal      int
+		wantRestricted bool
+		wantDeposit    bool
+	}{
+		{
+			name:      "Under Limit",
+			noShowAt:  []time.Time{daysAgo(1)},
+			wantCount: 1,
+			wantTotal: 1,
+		},
+		{
+			// Only no-shows within the period count
+			name:      "Outside Period",
+			noShowAt:  []time.Time{daysAgo(40), daysAgo(1)},
+			wantCount: 1,
+			wantTotal: 2,
+		},
+		{
+			name:           "Restricted",
+			noShowAt:       []time.Time{daysAgo(3), daysAgo(1)},
+			wantCount:      2,
+			wantTotal:      2,
+			wantRestricted: true,
+			wantDeposit:    true,
+		},
+		{
+			// Bookings are allowed again, with a deposit
+			name:        "Cooldown Over",
+			noShowAt:    []time.Time{daysAgo(20), daysAgo(10)},
+			wantCount:   2,
+			wantTotal:   2,
+			wantDeposit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := useTestRepo(t)
+			setNoShowPolicy(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			patientID := addPatient(t, "Patient1")
+
+			addNoShows(t, db, doctorID, patientID, tt.noShowAt...)
+
+			record, err := AppointmentService.GetNoShows(0, patientID, "patient")
+			if err != nil {
+				t.Fatalf("GetNoShows() error = %s", err.GetMessage())
+			}
+
+			if record.Count != tt.wantCount || record.Total != tt.wantTotal || record.DepositRequired != tt.wantDeposit {
+				t.Errorf("GetNoShows() = %+v, want %d no-shows, %d total, deposit %v", record, tt.wantCount, tt.wantTotal, tt.wantDeposit)
+			}
+
+			if tt.wantRestricted {
+				if want := tt.noShowAt[len(tt.noShowAt)-1].AddDate(0, 0, 7); record.RestrictedUntil == nil || !record.RestrictedUntil.Equal(want) {
+					t.Errorf("GetNoShows() restricted until %v, want %s", record.RestrictedUntil, want)
+				}
+			} else if record.RestrictedUntil != nil {
+				t.Errorf("GetNoShows() restricted until %s, want not restricted", record.RestrictedUntil)
+			}
+
+			booking, err := AppointmentService.Book("Doctor1", patientID, 0, startTimes[0], "")
+			if tt.wantRestricted {
+				if err == nil || !strings.HasPrefix(err.GetMessage(), "You missed 2 appointments") {
+					t.Errorf("Book() error = %v, want restricted", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Book() error = %s", err.GetMessage())
+			}
+
+			if booking.DepositRequired != tt.wantDeposit {
+				t.Errorf("Book() deposit required = %v, want %v", booking.DepositRequired, tt.wantDeposit)
+			}
+		})
+	}
+}
+
+func TestNoShowRestrictionMoving(t *testing.T) {
+	db := useTestRepo(t)
+
+	startTimes := weekly(1)
+	doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+	patientID := addPatient(t, "Patient1")
+
+	var appointIDs []int
+
+	for _, startTime := range []time.Time{startTimes[0], startTimes[0].Add(15 * time.Minute)} {
+		appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTime, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		appointIDs = append(appointIDs, appointID)
+	}
+
+	if err := AppointmentService.Cancel(appointIDs[1], patientID, "patient", ""); err != nil {
+		t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+	}
+
+	// The Patient is restricted after booking
+	setNoShowPolicy(t)
+	addNoShows(t, db, doctorID, patientID, time.Now().AddDate(0, 0, -2), time.Now().AddDate(0, 0, -1))
+
+	newStart := startTimes[0].Add(30 * time.Minute)
+
+	if _, err := AppointmentService.Book("Doctor1", patientID, 0, newStart, ""); err == nil {
+		t.Fatalf("Book() error = nil, want restricted")
+	}
+
+	// Moving a cancelled appointment would book a new one
+	if err := AppointmentService.Reschedule(appointIDs[1], patientID, "patient", "", newStart); err == nil {
+		t.Errorf("Reschedule() of cancelled appointment error = nil, want error")
+	}
+
+	// Appointments booked before are kept, and can be moved
+	if err := AppointmentService.Reschedule(appointIDs[0], patientID, "patient", "", newStart); err != nil {
+		t.Errorf("Reschedule() error = %s", err.GetMessage())
+	}
+
+	bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when getting bookings", err.GetMessage())
+	}
+
+	if len(bookings) != 1 || bookings[0].ID != appointIDs[0] || !bookings[0].StartTime.Equal(newStart) {
+		t.Errorf("upcoming appointments = %+v, want appointment id %d at %s", bookings, appointIDs[0], newStart)
+	}
+}
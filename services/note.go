@@ -0,0 +1,40 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+)
+
+// AddNote attaches a private visit note to the appointment. Only its Doctor
+// and their delegates can write notes.
+func (as *appointmentService) AddNote(appointID int, userID int, userType string, text string) (int, errors.AppointmentErr) {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return 0, err
+	}
+
+	if userType == "admin" {
+		return 0, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	if err := checkDoctorAccess(booking, userID, userType); err != nil {
+		return 0, err
+	}
+
+	return domain.Repo.AddNote(domain.Note{AppointmentID: booking.ID, AuthorID: userID, AuthorType: userType, Note: text})
+}
+
+// GetNotes returns the notes of the appointment to its Doctor, their
+// delegates and Admins.
+func (as *appointmentService) GetNotes(appointID int, userID int, userType string) ([]domain.Note, errors.AppointmentErr) {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return make([]domain.Note, 0), err
+	}
+
+	if err := checkDoctorAccess(booking, userID, userType); err != nil {
+		return make([]domain.Note, 0), err
+	}
+
+	return domain.Repo.GetNotes(booking.ID)
+}
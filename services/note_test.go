@@ -0,0 +1,196 @@
+package services
+
+import (
+	"appointment/domain"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const privateNote = "Private note on the visit"
+
+func TestNoteAccess(t *testing.T) {
+	tests := []struct {
+		name          string
+		userType      string
+		other         bool
+		wantAddStatus int
+		wantGetStatus int
+	}{
+		{
+			name:     "Doctor",
+			userType: "doctor",
+		},
+		{
+			name:     "Delegate",
+			userType: "delegate",
+		},
+		{
+			// Admins read notes but do not write them
+			name:          "Admin",
+			userType:      "admin",
+			wantAddStatus: http.StatusForbidden,
+		},
+		{
+			name:          "Other Doctor",
+			userType:      "doctor",
+			other:         true,
+			wantAddStatus: http.StatusForbidden,
+			wantGetStatus: http.StatusForbidden,
+		},
+		{
+			name:          "Other Delegate",
+			userType:      "delegate",
+			other:         true,
+			wantAddStatus: http.StatusForbidden,
+			wantGetStatus: http.StatusForbidden,
+		},
+		{
+			name:          "Patient",
+			userType:      "patient",
+			wantAddStatus: http.StatusForbidden,
+			wantGetStatus: http.StatusForbidden,
+		},
+		{
+			// The admin user type alone does not grant access
+			name:          "Unknown Admin",
+			userType:      "admin",
+			other:         true,
+			wantAddStatus: http.StatusForbidden,
+			wantGetStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			otherDoctorID := addDoctor(t, "Doctor2", 1)
+			patientID := addPatient(t, "Patient1")
+
+			appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTimes[0], "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+			}
+
+			if _, err := AppointmentService.AddNote(appointID, doctorID, "doctor", privateNote); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding note", err.GetMessage())
+			}
+
+			var userID int
+
+			switch tt.userType {
+			case "doctor":
+				userID = doctorID
+				if tt.other {
+					userID = otherDoctorID
+				}
+			case "delegate":
+				delegateOf := doctorID
+				if tt.other {
+					delegateOf = otherDoctorID
+				}
+
+				if userID, err = domain.Repo.CreateDelegateAccount(delegateOf, "Delegate1"); err != nil {
+					t.Fatalf("an error '%s' was not expected when creating delegate", err.GetMessage())
+				}
+			case "admin":
+				userID = 99
+				if !tt.other {
+					if userID, err = domain.Repo.CreateAdminAccount("Admin1"); err != nil {
+						t.Fatalf("an error '%s' was not expected when creating admin", err.GetMessage())
+					}
+				}
+			case "patient":
+				userID = patientID
+			}
+
+			_, err = AppointmentService.AddNote(appointID, userID, tt.userType, "Another note")
+			if tt.wantAddStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantAddStatus {
+					t.Errorf("AddNote() error = %v, want status %d", err, tt.wantAddStatus)
+				}
+			} else if err != nil {
+				t.Errorf("AddNote() error = %s", err.GetMessage())
+			}
+
+			notes, err := AppointmentService.GetNotes(appointID, userID, tt.userType)
+			if tt.wantGetStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantGetStatus {
+					t.Errorf("GetNotes() error = %v, want status %d", err, tt.wantGetStatus)
+				}
+
+				if len(notes) != 0 {
+					t.Errorf("GetNotes() = %+v, want none", notes)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetNotes() error = %s", err.GetMessage())
+			}
+
+			if len(notes) == 0 || notes[0].Note != privateNote {
+				t.Errorf("GetNotes() = %+v, want %q first", notes, privateNote)
+			}
+		})
+	}
+}
+
+func TestNotesNotListed(t *testing.T) {
+	useTestRepo(t)
+
+	startTimes := weekly(1)
+	doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+	patientID := addPatient(t, "Patient1")
+
+	appointID, err := domain.Repo.BookSlot(doctorID, patientID, patientID, startTimes[0], "Check-up")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+	}
+
+	if _, err := AppointmentService.AddNote(appointID, doctorID, "doctor", privateNote); err != nil {
+		t.Fatalf("an error '%s' was not expected when adding note", err.GetMessage())
+	}
+
+	appointments, _, err := AppointmentService.GetAppointments(domain.BookingFilter{AccountID: patientID, From: time.Now(), To: farFuture, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAppointments() error = %s", err.GetMessage())
+	}
+
+	bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when getting bookings", err.GetMessage())
+	}
+
+	booking, err := AppointmentService.GetAppointment(appointID, patientID, "patient")
+	if err != nil {
+		t.Fatalf("GetAppointment() error = %s", err.GetMessage())
+	}
+
+	listings := map[string]interface{}{
+		"GetAppointments()":    appointments,
+		"GetPatientBookings()": bookings,
+		"GetAppointment()":     booking,
+	}
+
+	for name, listing := range listings {
+		data, jsonErr := json.Marshal(listing)
+		if jsonErr != nil {
+			t.Fatalf("an error '%s' was not expected when encoding %s", jsonErr, name)
+		}
+
+		if !strings.Contains(string(data), "Check-up") {
+			t.Errorf("%s = %s, want the appointment listed", name, data)
+		}
+
+		if strings.Contains(string(data), privateNote) {
+			t.Errorf("%s = %s, want no notes", name, data)
+		}
+	}
+}
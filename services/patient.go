@@ -0,0 +1,110 @@
+package services
+
+import (
+	"appointment/config"
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+// farFuture bounds queries for all upcoming appointments.
+var farFuture = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// checkPatient checks that the Patient can take an appointment with the
+// Doctor at startTime, without overlapping their own appointments or going
+// over their quotas or being in their no-show cooldown. moving are the
+// appointments being moved, if any, which do not count against the Patient.
+func checkPatient(patientID int, doctorID int, startTime time.Time, moving ...int) errors.AppointmentErr {
+	now := time.Now()
+
+	if len(moving) == 0 {
+		if err := checkNoShows(patientID, now); err != nil {
+			return err
+		}
+	}
+
+	endTime := startTime.Add(domain.SlotMinutes * time.Minute)
+	dayStart := startTime.UTC().Truncate(24 * time.Hour)
+
+	from := dayStart
+	if now.Before(from) {
+		from = now
+	}
+
+	// Appointments last less than a day
+	bookings, err := domain.Repo.GetPatientBookings(patientID, from.Add(-24*time.Hour), farFuture)
+	if err != nil {
+		return err
+	}
+
+	var upcoming, withDoctor, onDay int
+
+	skip := make(map[int]bool, len(moving))
+	for _, appointmentID := range moving {
+		skip[appointmentID] = true
+	}
+
+	for _, booking := range bookings {
+		if skip[booking.ID] {
+			continue
+		}
+
+		if config.PatientOverlapCheck() && booking.StartTime.Before(endTime) && booking.EndTime().After(startTime) {
+			return errors.NewGeneralError(fmt.Sprintf("Appointment overlaps with your appointment id %d", booking.ID), nil)
+		}
+
+		if booking.StartTime.After(now) {
+			upcoming++
+
+			if booking.DoctorID == doctorID {
+				withDoctor++
+			}
+		}
+
+		if booking.StartTime.UTC().Truncate(24 * time.Hour).Equal(dayStart) {
+			onDay++
+		}
+	}
+
+	if limit := config.MaxFutureAppointments(); limit > 0 && upcoming >= limit {
+		return errors.NewGeneralError(fmt.Sprintf("You can have at most %d upcoming appointments", limit), nil)
+	}
+
+	if limit := config.MaxFutureAppointmentsPerDoctor(); limit > 0 && withDoctor >= limit {
+		return errors.NewGeneralError(fmt.Sprintf("You can have at most %d upcoming appointments with the same Doctor", limit), nil)
+	}
+
+	if limit := config.MaxAppointmentsPerDay(); limit > 0 && onDay >= limit {
+		return errors.NewGeneralError(fmt.Sprintf("You can have at most %d appointments per day", limit), nil)
+	}
+
+	return nil
+}
+
+// checkSeriesQuota checks that the upcoming appointment quotas of the Patient
+// leave room for count more appointments with the Doctor.
+func checkSeriesQuota(patientID int, doctorID int, count int) errors.AppointmentErr {
+	bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+	if err != nil {
+		return err
+	}
+
+	var withDoctor int
+
+	for _, booking := range bookings {
+		if booking.DoctorID == doctorID {
+			withDoctor++
+		}
+	}
+
+	if limit := config.MaxFutureAppointments(); limit > 0 && len(bookings)+count > limit {
+		return errors.NewGeneralError(fmt.Sprintf("You can have at most %d upcoming appointments", limit), nil)
+	}
+
+	if limit := config.MaxFutureAppointmentsPerDoctor(); limit > 0 && withDoctor+count > limit {
+		return errors.NewGeneralError(fmt.Sprintf("You can have at most %d upcoming appointments with the same Doctor", limit), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"appointment/domain"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCheckPatientOverlap(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    string
+		duration int
+		doctor   int
+		offset   time.Duration
+		wantErr  bool
+	}{
+		{
+			name:    "Other Doctor Same Time",
+			doctor:  1,
+			wantErr: true,
+		},
+		{
+			name:   "Other Doctor Right After",
+			doctor: 1,
+			offset: 15 * time.Minute,
+		},
+		{
+			name:   "Other Doctor Right Before",
+			doctor: 1,
+			offset: -15 * time.Minute,
+		},
+		{
+			// The slot after starts before the longer appointment ends
+			name:     "Other Doctor During Longer Appointment",
+			duration: 30,
+			doctor:   1,
+			offset:   15 * time.Minute,
+			wantErr:  true,
+		},
+		{
+			name:    "Same Doctor Group Slot",
+			wantErr: true,
+		},
+		{
+			name:   "Check Off",
+			check:  "false",
+			doctor: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := useTestRepo(t)
+
+			if len(tt.check) != 0 {
+				os.Setenv("PATIENT_OVERLAP_CHECK", tt.check)
+				defer os.Unsetenv("PATIENT_OVERLAP_CHECK")
+			}
+
+			startTimes := weekly(1)
+			doctorIDs := []int{addDoctor(t, "Doctor1", 2, startTimes...), addDoctor(t, "Doctor2", 1, startTimes...)}
+			patientID := addPatient(t, "Patient1")
+
+			appointID, err := domain.Repo.BookSlot(doctorIDs[0], patientID, patientID, startTimes[0], "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+			}
+
+			if tt.duration != 0 {
+				if _, err := db.Exec("UPDATE appointments SET duration_minutes=? WHERE id=?;", tt.duration, appointID); err != nil {
+					t.Fatalf("an error '%s' was not expected when setting duration", err)
+				}
+			}
+
+			err = checkPatient(patientID, doctorIDs[tt.doctor], startTimes[0].Add(tt.offset))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPatient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if want := fmt.Sprintf("Appointment overlaps with your appointment id %d", appointID); tt.wantErr && err.GetMessage() != want {
+				t.Errorf("checkPatient() error = %q, want %q", err.GetMessage(), want)
+			}
+
+			// The appointment being moved does not overlap itself
+			if err := checkPatient(patientID, doctorIDs[tt.doctor], startTimes[0].Add(tt.offset), appointID); err != nil {
+				t.Errorf("checkPatient() moving error = %s", err.GetMessage())
+			}
+		})
+	}
+}
+
+func TestCheckPatientQuotas(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		limit   string
+		doctor  int
+		week    int
+		hour    time.Duration
+		moving  bool
+		wantErr string
+	}{
+		{
+			name:    "Future Appointments",
+			env:     "MAX_FUTURE_APPOINTMENTS",
+			limit:   "2",
+			doctor:  1,
+			week:    1,
+			wantErr: "You can have at most 2 upcoming appointments",
+		},
+		{
+			name:   "Future Appointments Under Limit",
+			env:    "MAX_FUTURE_APPOINTMENTS",
+			limit:  "3",
+			doctor: 1,
+			week:   1,
+		},
+		{
+			// Moving an appointment does not add one
+			name:   "Future Appointments Moving",
+			env:    "MAX_FUTURE_APPOINTMENTS",
+			limit:  "2",
+			doctor: 1,
+			week:   1,
+			moving: true,
+		},
+		{
+			name:    "Future Appointments Per Doctor",
+			env:     "MAX_FUTURE_APPOINTMENTS_PER_DOCTOR",
+			limit:   "1",
+			week:    1,
+			wantErr: "You can have at most 1 upcoming appointments with the same Doctor",
+		},
+		{
+			name:   "Future Appointments Per Doctor Other Doctor",
+			env:    "MAX_FUTURE_APPOINTMENTS_PER_DOCTOR",
+			limit:  "1",
+			doctor: 1,
+			week:   1,
+		},
+		{
+			name:    "Appointments Per Day",
+			env:     "MAX_APPOINTMENTS_PER_DAY",
+			limit:   "2",
+			doctor:  1,
+			hour:    2 * time.Hour,
+			wantErr: "You can have at most 2 appointments per day",
+		},
+		{
+			name:   "Appointments Per Day Other Day",
+			env:    "MAX_APPOINTMENTS_PER_DAY",
+			limit:  "2",
+			doctor: 1,
+			week:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			os.Setenv(tt.env, tt.limit)
+			defer os.Unsetenv(tt.env)
+
+			// The Patient sees Doctor1 twice on the first day
+			startTimes := weekly(2)
+			doctorIDs := []int{addDoctor(t, "Doctor1", 1, startTimes...), addDoctor(t, "Doctor2", 1, startTimes...)}
+			patientID := addPatient(t, "Patient1")
+
+			var appointIDs []int
+
+			for _, startTime := range []time.Time{startTimes[0], startTimes[0].Add(15 * time.Minute)} {
+				appointID, err := domain.Repo.BookSlot(doctorIDs[0], patientID, patientID, startTime, "")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+				}
+
+				appointIDs = append(appointIDs, appointID)
+			}
+
+			var moving []int
+			if tt.moving {
+				moving = appointIDs[:1]
+			}
+
+			err := checkPatient(patientID, doctorIDs[tt.doctor], startTimes[tt.week].Add(tt.hour), moving...)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("checkPatient() error = %s", err.GetMessage())
+				}
+
+				return
+			}
+
+			if err == nil || err.GetMessage() != tt.wantErr {
+				t.Errorf("checkPatient() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
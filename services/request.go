@@ -0,0 +1,183 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// bookSlot books the slot for the Patient, or requests it if the Doctor
+// approves bookings, and returns the appointment.
+func bookSlot(doctorID int, patientID int, bookedBy int, startTime time.Time, reason string) (domain.Booking, errors.AppointmentErr) {
+	settings, err := domain.Repo.GetDoctorSettings(doctorID)
+	if err != nil {
+		return domain.Booking{}, err
+	}
+
+	var appointmentID int
+
+	if settings.ApprovalRequired {
+		appointmentID, err = domain.Repo.RequestSlot(doctorID, patientID, bookedBy, startTime, reason, requestExpiry(settings, startTime, time.Now()))
+	} else {
+		appointmentID, err = domain.Repo.BookSlot(doctorID, patientID, bookedBy, startTime, reason)
+	}
+
+	if err != nil {
+		return domain.Booking{}, err
+	}
+
+	if err := requireDeposit(patientID, appointmentID); err != nil {
+		return domain.Booking{ID: appointmentID}, err
+	}
+
+	return domain.Repo.GetBooking(appointmentID)
+}
+
+// checkApproval refuses to book the Doctor's slots in ways that skip their
+// approval.
+func checkApproval(doctorID int) errors.AppointmentErr {
+	settings, err := domain.Repo.GetDoctorSettings(doctorID)
+	if err != nil {
+		return err
+	}
+
+	if settings.ApprovalRequired {
+		return errors.NewGeneralError("Doctor approves every booking, request appointments on /book instead", nil)
+	}
+
+	return nil
+}
+
+// moveExpiry tells until when an appointment the user moves to the Doctor's
+// slot at startTime waits for their approval as a booking request, or the
+// zero time if it needs none. The Doctor and their delegates approve their own
+// moves.
+func moveExpiry(doctorID int, startTime time.Time, userID int, userType string) (time.Time, errors.AppointmentErr) {
+	settings, err := domain.Repo.GetDoctorSettings(doctorID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !settings.ApprovalRequired {
+		return time.Time{}, nil
+	}
+
+	if actingFor, err := doctorOf(userID, userType); err == nil && actingFor == doctorID {
+		return time.Time{}, nil
+	}
+
+	return requestExpiry(settings, startTime, time.Now()), nil
+}
+
+// GetRequests returns the booking requests pending the approval of the Doctor
+// userID, or of the Doctor of the delegate userID.
+func (as *appointmentService) GetRequests(userID int, userType string) ([]domain.Booking, errors.AppointmentErr) {
+	doctorID, err := doctorOf(userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.Repo.GetRequests(doctorID)
+}
+
+// ReviewRequest approves or declines the booking request, and passes the
+// message of the Doctor on to the Patient. Declined requests free their slot.
+func (as *appointmentService) ReviewRequest(appointID int, userID int, userType string, approve bool, message string) (domain.Booking, errors.AppointmentErr) {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return booking, err
+	}
+
+	if err := checkDoctorAccess(booking, userID, userType); err != nil {
+		return booking, err
+	}
+
+	if booking.Status != domain.StatusRequested {
+		return booking, errors.NewGeneralError(fmt.Sprintf("appointment id %d is %s and not pending approval", appointID, booking.Status), nil)
+	}
+
+	if booking.RequestExpiresAt != nil && !time.Now().Before(*booking.RequestExpiresAt) {
+		return booking, errors.NewGeneralError(fmt.Sprintf("request for appointment id %d has expired", appointID), nil)
+	}
+
+	doctor, err := domain.Repo.GetDoctor(booking.DoctorID)
+	if err != nil {
+		return booking, err
+	}
+
+	slot := booking.StartTime.UTC().Format(time.RFC3339)
+
+	if approve {
+		if err := domain.Repo.UpdateStatus(appointID, domain.StatusRequested, domain.StatusConfirmed, userID, userType); err != nil {
+			return booking, err
+		}
+
+		notify(accountOf(booking), withMessage(fmt.Sprintf("Doctor %s approved your appointment request at %s. Your appointment id is %d.", doctor.Name, slot, appointID), message))
+	} else {
+		if err := domain.Repo.CancelAppointment(appointID, userID, userType, message, false); err != nil {
+			return booking, err
+		}
+
+		notify(accountOf(booking), withMessage(fmt.Sprintf("Doctor %s declined your appointment request at %s.", doctor.Name, slot), message))
+
+		// Offer freed slot to waitlist
+		promoteWaitlist(booking.DoctorID, booking.StartTime)
+	}
+
+	return domain.Repo.GetBooking(appointID)
+}
+
+// ExpireRequests declines the booking requests the Doctors did not approve in
+// time, and returns how many expired.
+func (as *appointmentService) ExpireRequests() (int, errors.AppointmentErr) {
+	expired, err := domain.Repo.ExpireRequests(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, booking := range expired {
+		doctor, err := domain.Repo.GetDoctor(booking.DoctorID)
+		if err != nil {
+			log.Printf("Error occured while fetching doctor for expired request : %s\n", err.GetError())
+
+			continue
+		}
+
+		notify(accountOf(booking), fmt.Sprintf("Your appointment request with Doctor %s at %s expired before it was approved.", doctor.Name, booking.StartTime.UTC().Format(time.RFC3339)))
+
+		promoteWaitlist(booking.DoctorID, booking.StartTime)
+	}
+
+	return len(expired), nil
+}
+
+// doctorOf returns the Doctor the Doctor or delegate userID acts for.
+func doctorOf(userID int, userType string) (int, errors.AppointmentErr) {
+	switch userType {
+	case "doctor":
+		return userID, nil
+	case "delegate":
+		return domain.Repo.GetDelegateDoctor(userID)
+	}
+
+	return 0, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+}
+
+// accountOf returns the Patient account notified about the appointment.
+func accountOf(booking domain.Booking) int {
+	if booking.AccountID != 0 {
+		return booking.AccountID
+	}
+
+	return booking.PatientID
+}
+
+func withMessage(notification string, message string) string {
+	if len(message) == 0 {
+		return notification
+	}
+
+	return notification + " Message from the Doctor: " + message
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+)
+
+func (as *appointmentService) GetDoctor(doctorID int) (domain.Doctor, errors.AppointmentErr) {
+	return domain.Repo.GetDoctor(doctorID)
+}
+
+// GetSchedule returns the schedule of the Doctor. Schedules of other Doctors
+// are not found.
+func (as *appointmentService) GetSchedule(doctorID int, scheduleID int) (domain.Schedule, errors.AppointmentErr) {
+	schedule, err := domain.Repo.GetSchedule(scheduleID)
+	if err != nil {
+		return schedule, err
+	}
+
+	if schedule.DoctorID != doctorID {
+		return domain.Schedule{}, errors.NewNotFoundError(fmt.Sprintf("schedule id %d does not exist for Doctor %d", scheduleID, doctorID), fmt.Errorf("schedule belongs to another doctor"))
+	}
+
+	return schedule, nil
+}
+
+// UpdateSchedule replaces the schedule of the Doctor, keeping its bookings.
+func (as *appointmentService) UpdateSchedule(schedule domain.Schedule) errors.AppointmentErr {
+	if _, err := as.GetSchedule(schedule.DoctorID, schedule.ID); err != nil {
+		return err
+	}
+
+	// Single patient slots unless specified
+	if schedule.Capacity == 0 {
+		schedule.Capacity = 1
+	}
+
+	return domain.Repo.UpdateSchedule(schedule)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"appointment/domain"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAddSchedule(t *testing.T) {
+	// Blocks of next week against the block from 10:00 until 12:00
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 7)
+	at := func(hour int, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	tests := []struct {
+		name      string
+		startTime time.Time
+		endTime   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "Same Block",
+			startTime: at(10, 0),
+			endTime:   at(12, 0),
+			wantErr:   true,
+		},
+		{
+			name:      "Starts Inside",
+			startTime: at(11, 0),
+			endTime:   at(13, 0),
+			wantErr:   true,
+		},
+		{
+			name:      "Ends Inside",
+			startTime: at(9, 0),
+			endTime:   at(10, 15),
+			wantErr:   true,
+		},
+		{
+			name:      "Inside",
+			startTime: at(10, 30),
+			endTime:   at(11, 30),
+			wantErr:   true,
+		},
+		{
+			name:      "Contains",
+			startTime: at(9, 0),
+			endTime:   at(13, 0),
+			wantErr:   true,
+		},
+		{
+			name:      "Before",
+			startTime: at(9, 0),
+			endTime:   at(10, 0),
+		},
+		{
+			name:      "After",
+			startTime: at(12, 0),
+			endTime:   at(13, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			doctorID := addDoctor(t, "Doctor1", 1)
+
+			if _, err := AppointmentService.AddSchedule(doctorID, at(10, 0), at(12, 0), 1, ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+			}
+
+			_, err := AppointmentService.AddSchedule(doctorID, tt.startTime, tt.endTime, 1, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && err.GetStatus() != http.StatusBadRequest {
+				t.Errorf("AddSchedule() status = %d, want %d", err.GetStatus(), http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	useTestRepo(t)
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 7)
+	doctorID := addDoctor(t, "Doctor1", 1)
+
+	var scheduleIDs []int
+
+	for _, hour := range []time.Duration{10, 14} {
+		scheduleID, err := AppointmentService.AddSchedule(doctorID, day.Add(hour*time.Hour), day.Add((hour+2)*time.Hour), 1, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+		}
+
+		scheduleIDs = append(scheduleIDs, scheduleID)
+	}
+
+	// The block may overlap its own times, but not the other block
+	schedule := domain.Schedule{ID: scheduleIDs[0], DoctorID: doctorID, StartTime: day.Add(11 * time.Hour), EndTime: day.Add(13 * time.Hour), Capacity: 1}
+
+	if err := AppointmentService.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("UpdateSchedule() error = %s", err.GetMessage())
+	}
+
+	schedule.EndTime = day.Add(15 * time.Hour)
+
+	if err := AppointmentService.UpdateSchedule(schedule); err == nil || err.GetStatus() != http.StatusConflict {
+		t.Errorf("UpdateSchedule() error = %v, want status %d", err, http.StatusConflict)
+	}
+}
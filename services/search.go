@@ -0,0 +1,71 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"strconv"
+	"time"
+)
+
+// maxSearchDays bounds the date range of a slot search.
+const maxSearchDays = 90
+
+// SearchSlots returns the earliest limit slots matching the search that can
+// be booked, across all matching Doctors.
+func (as *appointmentService) SearchSlots(search domain.SlotSearch, limit int) ([]domain.Appointment, errors.AppointmentErr) {
+	available := make([]domain.Appointment, 0)
+
+	// Keep the location the time of day is given in
+	now := time.Now()
+	if search.From.Before(now) {
+		search.From = now.In(search.From.Location())
+	}
+
+	if !search.To.After(search.From) {
+		return available, errors.NewGeneralError("Search range must end after it starts", nil)
+	}
+
+	if search.To.Sub(search.From) > maxSearchDays*24*time.Hour {
+		return available, errors.NewGeneralError("Search range cannot be longer than 90 days", nil)
+	}
+
+	slots, err := domain.Repo.SearchSlots(search)
+	if err != nil {
+		return available, err
+	}
+
+	settings := make(map[string]domain.DoctorSettings)
+	holidays := make(map[string]map[string]string)
+
+	for _, slot := range slots {
+		if _, ok := settings[slot.DoctorID]; !ok {
+			doctorID, _ := strconv.Atoi(slot.DoctorID)
+
+			settings[slot.DoctorID], err = domain.Repo.GetDoctorSettings(doctorID)
+			if err != nil {
+				return available, err
+			}
+
+			holidays[slot.DoctorID], err = domain.Repo.GetHolidays(doctorID, search.From, search.To)
+			if err != nil {
+				return available, err
+			}
+		}
+
+		if _, ok := holidays[slot.DoctorID][slot.StartTime.Format(domain.DateFormat)]; ok {
+			continue
+		}
+
+		if checkBookingWindow(settings[slot.DoctorID], slot.StartTime, now) != nil {
+			continue
+		}
+
+		available = append(available, slot)
+
+		if len(available) == limit {
+			break
+		}
+	}
+
+	return available, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"appointment/domain"
+	"testing"
+	"time"
+)
+
+func TestSearchSlots(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+
+	tests := []struct {
+		name       string
+		days       int
+		location   *time.Location
+		fromMinute int
+		toMinute   int
+		want       int
+		wantErr    bool
+	}{
+		{
+			name:     "Whole Day",
+			days:     7,
+			location: time.UTC,
+			toMinute: 24 * 60,
+			want:     4,
+		},
+		{
+			// The schedule is 10:00 to 11:00 UTC
+			name:       "Time Of Day In UTC",
+			days:       7,
+			location:   time.UTC,
+			fromMinute: 10*60 + 30,
+			toMinute:   24 * 60,
+			want:       2,
+		},
+		{
+			// 12:30 to 13:00 in the zone of from
+			name:       "Time Of Day In Zone Of From",
+			days:       7,
+			location:   berlin,
+			fromMinute: 12*60 + 30,
+			toMinute:   24 * 60,
+			want:       2,
+		},
+		{
+			name:     "Longest Range",
+			days:     maxSearchDays,
+			location: time.UTC,
+			toMinute: 24 * 60,
+			want:     4,
+		},
+		{
+			name:     "Range Too Long",
+			days:     maxSearchDays + 1,
+			location: time.UTC,
+			toMinute: 24 * 60,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+
+			from := startTimes[0].Add(-time.Hour).In(tt.location)
+			search := domain.SlotSearch{DoctorIDs: []int{doctorID}, From: from, To: from.AddDate(0, 0, tt.days), FromMinute: tt.fromMinute, ToMinute: tt.toMinute}
+
+			slots, err := AppointmentService.SearchSlots(search, 100)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SearchSlots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(slots) != tt.want {
+				t.Errorf("SearchSlots() found %d slots, want %d", len(slots), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BookSeries books a recurring series for the Patient account userID, or its
+// dependent. Every occurrence is checked first. If any of them cannot be
+// booked, or for a dry run, nothing is booked and the series tells why for
+// each occurrence.
+func (as *appointmentService) BookSeries(request domain.SeriesRequest, userID int) (domain.Series, errors.AppointmentErr) {
+	series := domain.Series{IntervalDays: request.IntervalDays, Occurrences: make([]domain.Occurrence, 0, request.Occurrences)}
+
+	// Weekly unless specified
+	if series.IntervalDays == 0 {
+		series.IntervalDays = 7
+	}
+
+	patientID, err := patientFor(userID, request.DependentID)
+	if err != nil {
+		return series, err
+	}
+
+	doctorID, err := domain.Repo.GetDoctorID(request.DoctorName)
+	if err != nil {
+		return series, err
+	}
+
+	series.DoctorID, series.PatientID = doctorID, patientID
+
+	if err := checkApproval(doctorID); err != nil {
+		return series, err
+	}
+
+	for i := 0; i < request.Occurrences; i++ {
+		occurrence := domain.Occurrence{StartTime: request.StartTime.AddDate(0, 0, i*series.IntervalDays)}
+
+		if err := checkSlot(doctorID, occurrence.StartTime); err != nil {
+			occurrence.Error = err.GetMessage()
+		} else if err := checkPatient(patientID, doctorID, occurrence.StartTime); err != nil {
+			occurrence.Error = err.GetMessage()
+		}
+
+		series.Occurrences = append(series.Occurrences, occurrence)
+	}
+
+	if series.Conflicts() != 0 {
+		return series, nil
+	}
+
+	if err := checkSeriesQuota(patientID, doctorID, request.Occurrences); err != nil {
+		return series, err
+	}
+
+	if request.DryRun {
+		return series, nil
+	}
+
+	series, err = domain.Repo.BookSeries(series, userID, request.Reason)
+	if err != nil {
+		return series, err
+	}
+
+	for _, occurrence := range series.Occurrences {
+		if err := requireDeposit(patientID, occurrence.AppointmentID); err != nil {
+			return series, err
+		}
+	}
+
+	return series, nil
+}
+
+// CancelSeries cancels the appointment and the later occurrences of its
+// series, and returns the IDs of the cancelled appointments. Either all or
+// none of them are cancelled.
+func (as *appointmentService) CancelSeries(appointID int, userID int, userType string, reason string) ([]int, errors.AppointmentErr) {
+	cancelled := make([]int, 0)
+
+	bookings, err := seriesFrom(appointID, userID, userType)
+	if err != nil {
+		return cancelled, err
+	}
+
+	now := time.Now()
+	late := make([]bool, 0, len(bookings))
+	ids := make([]int, 0, len(bookings))
+
+	for _, booking := range bookings {
+		isLate, err := checkCancel(booking, userType, reason, now)
+		if err != nil {
+			return cancelled, errors.NewGeneralError(fmt.Sprintf("appointment id %d cannot be cancelled: %s", booking.ID, err.GetMessage()), nil)
+		}
+
+		late = append(late, isLate)
+		ids = append(ids, booking.ID)
+	}
+
+	if err := domain.Repo.CancelAppointments(bookings, late, userID, userType, strings.TrimSpace(reason)); err != nil {
+		return cancelled, err
+	}
+
+	// Offer freed slots to waitlist
+	for _, booking := range bookings {
+		promoteWaitlist(booking.DoctorID, booking.StartTime)
+	}
+
+	return ids, nil
+}
+
+// RescheduleSeries moves the appointment to startTime, with the Doctor named
+// doctorName or the same Doctor, and shifts the later occurrences of its
+// series by as much. Either all or none of them are moved.
+func (as *appointmentService) RescheduleSeries(appointID int, userID int, userType string, doctorName string, startTime time.Time) ([]int, errors.AppointmentErr) {
+	moved := make([]int, 0)
+
+	bookings, err := seriesFrom(appointID, userID, userType)
+	if err != nil {
+		return moved, err
+	}
+
+	doctorID := bookings[0].DoctorID
+
+	if len(doctorName) != 0 {
+		doctorID, err = domain.Repo.GetDoctorID(doctorName)
+		if err != nil {
+			return moved, err
+		}
+	}
+
+	shift := startTime.Sub(bookings[0].StartTime)
+
+	if doctorID == bookings[0].DoctorID && shift == 0 {
+		return moved, errors.NewGeneralError("Appointment is already booked for this slot", nil)
+	}
+
+	expiresAt, err := moveExpiry(doctorID, startTime, userID, userType)
+	if err != nil {
+		return moved, err
+	}
+
+	// Series cannot be requested, like when booking them
+	if !expiresAt.IsZero() {
+		return moved, errors.NewGeneralError("Doctor approves every booking, move the appointments one at a time instead", nil)
+	}
+
+	// Occurrences moving onto the slot of another take the seat it frees
+	type slot struct {
+		doctorID  int
+		startTime int64
+	}
+
+	vacated := make(map[slot]bool, len(bookings))
+	ids := make([]int, 0, len(bookings))
+
+	for _, booking := range bookings {
+		vacated[slot{booking.DoctorID, booking.StartTime.Unix()}] = true
+		ids = append(ids, booking.ID)
+	}
+
+	startTimes := make([]time.Time, 0, len(bookings))
+
+	for _, booking := range bookings {
+		newStart := booking.StartTime.Add(shift)
+
+		// Check If new slot can be booked
+		err := checkSlot(doctorID, newStart)
+		if err != nil && err.GetStatus() == http.StatusConflict && vacated[slot{doctorID, newStart.Unix()}] {
+			err = nil
+		}
+
+		if err != nil {
+			return moved, errors.NewGeneralError(fmt.Sprintf("appointment id %d cannot be moved: %s", booking.ID, err.GetMessage()), nil)
+		}
+
+		if err := checkPatient(booking.PatientID, doctorID, newStart, ids...); err != nil {
+			return moved, errors.NewGeneralError(fmt.Sprintf("appointment id %d cannot be moved: %s", booking.ID, err.GetMessage()), nil)
+		}
+
+		startTimes = append(startTimes, newStart)
+	}
+
+	// Move the latest first when moving later, so that each slot is vacated
+	// before the occurrence before it moves in
+	order, orderTimes := ids, startTimes
+	if shift > 0 {
+		order, orderTimes = make([]int, 0, len(ids)), make([]time.Time, 0, len(ids))
+
+		for i := len(ids) - 1; i >= 0; i-- {
+			order = append(order, ids[i])
+			orderTimes = append(orderTimes, startTimes[i])
+		}
+	}
+
+	if err := domain.Repo.RescheduleAppointments(order, doctorID, orderTimes, userID, userType); err != nil {
+		return moved, err
+	}
+
+	taken := make(map[slot]bool, len(startTimes))
+	for _, newStart := range startTimes {
+		taken[slot{doctorID, newStart.Unix()}] = true
+	}
+
+	// Offer freed slots to waitlist
+	for _, booking := range bookings {
+		if !taken[slot{booking.DoctorID, booking.StartTime.Unix()}] {
+			promoteWaitlist(booking.DoctorID, booking.StartTime)
+		}
+	}
+
+	return ids, nil
+}
+
+// seriesFrom returns the appointment and the later open occurrences of its
+// series, if the user can manage them.
+func seriesFrom(appointID int, userID int, userType string) ([]domain.Booking, errors.AppointmentErr) {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(booking, userID, userType) {
+		return nil, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	if booking.SeriesID == 0 {
+		return nil, errors.NewGeneralError(fmt.Sprintf("appointment id %d is not part of a series", appointID), nil)
+	}
+
+	if !booking.Open() {
+		return nil, errors.NewGeneralError(fmt.Sprintf("appointment id %d is %s", appointID, booking.Status), nil)
+	}
+
+	bookings, err := domain.Repo.GetSeriesBookings(booking.SeriesID, booking.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bookings) == 0 || bookings[0].ID != booking.ID {
+		return nil, errors.NewConflictError(fmt.Sprintf("appointment id %d has changed, try again", appointID), nil)
+	}
+
+	return bookings, nil
+}
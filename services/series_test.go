@@ -0,0 +1,239 @@
+package services
+
+import (
+	"appointment/domain"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBookSeries(t *testing.T) {
+	tests := []struct {
+		name          string
+		occurrences   int
+		dryRun        bool
+		taken         int
+		quota         string
+		wantConflicts int
+		wantBooked    int
+		wantStatus    int
+	}{
+		{
+			name:        "Multi-Week Series",
+			occurrences: 4,
+			wantBooked:  4,
+		},
+		{
+			name:        "Dry Run",
+			occurrences: 4,
+			dryRun:      true,
+		},
+		{
+			// The third week is booked by someone else
+			name:          "Conflicts",
+			occurrences:   4,
+			taken:         3,
+			wantConflicts: 1,
+		},
+		{
+			name:        "Over Quota",
+			occurrences: 4,
+			quota:       "3",
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			if len(tt.quota) != 0 {
+				os.Setenv("MAX_FUTURE_APPOINTMENTS_PER_DOCTOR", tt.quota)
+				defer os.Unsetenv("MAX_FUTURE_APPOINTMENTS_PER_DOCTOR")
+			}
+
+			startTimes := weekly(tt.occurrences)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			patientID := addPatient(t, "Patient1")
+
+			if tt.taken != 0 {
+				otherID := addPatient(t, "Patient2")
+
+				if _, err := domain.Repo.BookSlot(doctorID, otherID, otherID, startTimes[tt.taken-1], ""); err != nil {
+					t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+				}
+			}
+
+			request := domain.SeriesRequest{DoctorName: "Doctor1", StartTime: startTimes[0], Occurrences: tt.occurrences, DryRun: tt.dryRun}
+
+			series, err := AppointmentService.BookSeries(request, patientID)
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Fatalf("BookSeries() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("BookSeries() error = %s", err.GetMessage())
+			}
+
+			if got := series.Conflicts(); got != tt.wantConflicts {
+				t.Errorf("BookSeries() conflicts = %d, want %d", got, tt.wantConflicts)
+			}
+
+			bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting bookings", err.GetMessage())
+			}
+
+			if len(bookings) != tt.wantBooked {
+				t.Errorf("BookSeries() booked %d, want %d", len(bookings), tt.wantBooked)
+			}
+		})
+	}
+}
+
+func TestRescheduleSeries(t *testing.T) {
+	tests := []struct {
+		name       string
+		capacity   int
+		shift      time.Duration
+		wantStatus int
+	}{
+		{
+			// Each occurrence moves onto the slot of the next one
+			name:     "One Interval Later",
+			capacity: 1,
+			shift:    7 * 24 * time.Hour,
+		},
+		{
+			name:     "One Interval Later Group Slot",
+			capacity: 2,
+			shift:    7 * 24 * time.Hour,
+		},
+		{
+			name:     "Same Day",
+			capacity: 1,
+			shift:    15 * time.Minute,
+		},
+		{
+			// The last occurrence would land outside the schedule
+			name:       "Two Intervals Later",
+			capacity:   1,
+			shift:      14 * 24 * time.Hour,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(4)
+			addDoctor(t, "Doctor1", tt.capacity, startTimes...)
+			patientID := addPatient(t, "Patient1")
+
+			request := domain.SeriesRequest{DoctorName: "Doctor1", StartTime: startTimes[0], Occurrences: 3}
+
+			series, err := AppointmentService.BookSeries(request, patientID)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking series", err.GetMessage())
+			}
+
+			moved, err := AppointmentService.RescheduleSeries(series.Occurrences[0].AppointmentID, patientID, "patient", "", startTimes[0].Add(tt.shift))
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Fatalf("RescheduleSeries() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RescheduleSeries() error = %s", err.GetMessage())
+			}
+
+			if len(moved) != len(series.Occurrences) {
+				t.Fatalf("RescheduleSeries() moved %d, want %d", len(moved), len(series.Occurrences))
+			}
+
+			for i, occurrence := range series.Occurrences {
+				booking, err := domain.Repo.GetBooking(moved[i])
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when getting booking", err.GetMessage())
+				}
+
+				if want := occurrence.StartTime.Add(tt.shift); !booking.StartTime.Equal(want) {
+					t.Errorf("appointment id %d starts at %s, want %s", booking.ID, booking.StartTime, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCancelSeries(t *testing.T) {
+	tests := []struct {
+		name          string
+		reason        string
+		requireReason bool
+		wantCancelled int
+		wantStatus    int
+	}{
+		{
+			name:          "OK",
+			wantCancelled: 3,
+		},
+		{
+			// Nothing is cancelled when one occurrence cannot be
+			name:          "Reason Required",
+			requireReason: true,
+			wantStatus:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(3)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			patientID := addPatient(t, "Patient1")
+
+			request := domain.SeriesRequest{DoctorName: "Doctor1", StartTime: startTimes[0], Occurrences: 3}
+
+			series, err := AppointmentService.BookSeries(request, patientID)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking series", err.GetMessage())
+			}
+
+			if err := domain.Repo.SaveDoctorSettings(domain.DoctorSettings{DoctorID: doctorID, RequireCancellationReason: tt.requireReason}); err != nil {
+				t.Fatalf("an error '%s' was not expected when saving settings", err.GetMessage())
+			}
+
+			cancelled, err := AppointmentService.CancelSeries(series.Occurrences[0].AppointmentID, patientID, "patient", tt.reason)
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Fatalf("CancelSeries() error = %v, want status %d", err, tt.wantStatus)
+				}
+			} else if err != nil {
+				t.Fatalf("CancelSeries() error = %s", err.GetMessage())
+			}
+
+			if len(cancelled) != tt.wantCancelled {
+				t.Errorf("CancelSeries() cancelled %d, want %d", len(cancelled), tt.wantCancelled)
+			}
+
+			bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting bookings", err.GetMessage())
+			}
+
+			if want := 3 - tt.wantCancelled; len(bookings) != want {
+				t.Errorf("%d appointments left, want %d", len(bookings), want)
+			}
+		})
+	}
+}
@@ -4,26 +4,70 @@ import (
 	"appointment/domain"
 	"appointment/errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 )
 
 var AppointmentService appointmentServiceInterface = &appointmentService{}
 
 type appointmentServiceInterface interface {
-	CreateDoctorAccount(string) (int, errors.AppointmentErr)
-	CreatePatientAccount(string) (int, errors.AppointmentErr)
-	AddSchedule(int, time.Time, time.Time) errors.AppointmentErr
-	Book(string, int, time.Time) (int, errors.AppointmentErr)
-	ListSchedule(string) ([]domain.Appointment, errors.AppointmentErr)
-	Cancel(int, int, string) errors.AppointmentErr
+	CreateDoctorAccount(domain.Doctor) (int, errors.AppointmentErr)
+	CreatePatientAccount(string, string) (int, errors.AppointmentErr)
+	CreateAdminAccount(string) (int, errors.AppointmentErr)
+	CreateDelegateAccount(int, string) (int, errors.AppointmentErr)
+	AddSchedule(int, time.Time, time.Time, int, string) (int, errors.AppointmentErr)
+	GetSchedule(int, int) (domain.Schedule, errors.AppointmentErr)
+	UpdateSchedule(domain.Schedule) errors.AppointmentErr
+	GetDoctor(int) (domain.Doctor, errors.AppointmentErr)
+	GetAppointment(int, int, string) (domain.Booking, errors.AppointmentErr)
+	Book(string, int, int, time.Time, string) (domain.Booking, errors.AppointmentErr)
+	BookForPatient(string, int, string, int, time.Time, string) (domain.Booking, errors.AppointmentErr)
+	ListSchedule(string, domain.ScheduleFilter) ([]domain.Appointment, int, domain.ScheduleSummary, errors.AppointmentErr)
+	Cancel(int, int, string, string) errors.AppointmentErr
+	Reschedule(int, int, string, string, time.Time) errors.AppointmentErr
+	BookSeries(domain.SeriesRequest, int) (domain.Series, errors.AppointmentErr)
+	CancelSeries(int, int, string, string) ([]int, errors.AppointmentErr)
+	RescheduleSeries(int, int, string, string, time.Time) ([]int, errors.AppointmentErr)
+	UpdateStatus(int, int, string, string, string) (domain.Booking, errors.AppointmentErr)
+	GetRequests(int, string) ([]domain.Booking, errors.AppointmentErr)
+	ReviewRequest(int, int, string, bool, string) (domain.Booking, errors.AppointmentErr)
+	ExpireRequests() (int, errors.AppointmentErr)
+	ExpireHolds() (int, errors.AppointmentErr)
+	MarkNoShows() (int, errors.AppointmentErr)
+	GetNoShows(int, int, string) (domain.NoShowRecord, errors.AppointmentErr)
+	GetCancellationReport(int, string, int, time.Time, time.Time) (domain.CancellationReport, errors.AppointmentErr)
+	JoinWaitlist(int, string, *time.Time, time.Time, bool) (domain.WaitlistEntry, errors.AppointmentErr)
+	GetWaitlist(int) ([]domain.WaitlistEntry, errors.AppointmentErr)
+	LeaveWaitlist(int, int) errors.AppointmentErr
+	GetNotifications(int) ([]domain.Notification, errors.AppointmentErr)
+	Hold(string, int, time.Time) (domain.Hold, errors.AppointmentErr)
+	ConfirmHold(int, int) (domain.Booking, errors.AppointmentErr)
+	ReleaseHold(int, int) errors.AppointmentErr
+	AddNote(int, int, string, string) (int, errors.AppointmentErr)
+	GetNotes(int, int, string) ([]domain.Note, errors.AppointmentErr)
+	SearchSlots(domain.SlotSearch, int) ([]domain.Appointment, errors.AppointmentErr)
+	GetFreeBusy([]int, time.Time, time.Time) ([]domain.FreeBusy, errors.AppointmentErr)
+	GetAgenda(int, string, time.Time) (domain.Agenda, errors.AppointmentErr)
+	GetCalendarFeed(int, string, bool) (domain.CalendarFeed, errors.AppointmentErr)
+	GetCalendar(string) (string, errors.AppointmentErr)
+	AddDependent(int, string) (int, errors.AppointmentErr)
+	GetDependents(int) ([]domain.Dependent, errors.AppointmentErr)
+	GetAppointments(domain.BookingFilter) ([]domain.AccountBooking, int, errors.AppointmentErr)
+	ImportHolidays(string, string, []byte) (int, errors.AppointmentErr)
+	OptInHoliday(int, time.Time) errors.AppointmentErr
+	GetDoctorSettings(int) (domain.DoctorSettings, errors.AppointmentErr)
+	UpdateDoctorSettings(domain.DoctorSettings) errors.AppointmentErr
+	ReserveIdempotencyKey(string, string, string) (domain.IdempotencyRecord, bool, errors.AppointmentErr)
+	SaveIdempotentResponse(string, string, int, string, []byte) errors.AppointmentErr
 }
 
 type appointmentService struct{}
 
-func (as *appointmentService) CreateDoctorAccount(name string) (int, errors.AppointmentErr) {
+func (as *appointmentService) CreateDoctorAccount(doctor domain.Doctor) (int, errors.AppointmentErr) {
 	var id int
 
-	id, err := domain.Repo.CreateDoctorAccount(name)
+	id, err := domain.Repo.CreateDoctorAccount(doctor)
 	if err != nil {
 		return id, err
 	}
@@ -31,10 +75,10 @@ func (as *appointmentService) CreateDoctorAccount(name string) (int, errors.Appo
 	return id, nil
 }
 
-func (as *appointmentService) CreatePatientAccount(name string) (int, errors.AppointmentErr) {
+func (as *appointmentService) CreatePatientAccount(name string, contact string) (int, errors.AppointmentErr) {
 	var id int
 
-	id, err := domain.Repo.CreatePatientAccount(name)
+	id, err := domain.Repo.CreatePatientAccount(name, contact)
 	if err != nil {
 		return id, err
 	}
@@ -42,107 +86,465 @@ func (as *appointmentService) CreatePatientAccount(name string) (int, errors.App
 	return id, nil
 }
 
-func (as *appointmentService) AddSchedule(doctorID int, startTime time.Time, endTime time.Time) errors.AppointmentErr {
-	// Allow current days bookings only
-	year, month, day := time.Now().Date()
-	today := time.Date(year, month, day, 23, 59, 59, 0, &time.Location{})
+func (as *appointmentService) CreateAdminAccount(name string) (int, errors.AppointmentErr) {
+	var id int
+
+	id, err := domain.Repo.CreateAdminAccount(name)
+	if err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
+
+func (as *appointmentService) CreateDelegateAccount(doctorID int, name string) (int, errors.AppointmentErr) {
+	var id int
 
-	if startTime.After(today) {
-		return errors.NewGeneralError("Schedule can be created for current day only", nil)
+	id, err := domain.Repo.CreateDelegateAccount(doctorID, name)
+	if err != nil {
+		return id, err
 	}
 
+	return id, nil
+}
+
+func (as *appointmentService) AddSchedule(doctorID int, startTime time.Time, endTime time.Time, capacity int, appointmentType string) (int, errors.AppointmentErr) {
 	// Check If Schedule already exists for Doctor
 	scheduleExists, err := domain.Repo.CheckScheduleExists(doctorID, startTime, endTime)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if scheduleExists {
-		return errors.NewGeneralError("Schedule already exists ", nil)
+		return 0, errors.NewGeneralError("Schedule already exists ", nil)
 	}
 
 	// Check If Schedule overlaps with existing
 	scheduleOverlaps, err := domain.Repo.CheckScheduleOverlaps(doctorID, startTime, endTime)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if scheduleOverlaps {
-		return errors.NewGeneralError("Schedule overlaps with existing schedule", nil)
+		return 0, errors.NewGeneralError("Schedule overlaps with existing schedule", nil)
 	}
 
-	// Else Add Schedule
-	err = domain.Repo.AddSchedule(doctorID, startTime, endTime)
-	if err != nil {
-		return err
+	// Single patient slots unless specified
+	if capacity == 0 {
+		capacity = 1
 	}
 
-	return nil
+	// Else Add Schedule
+	return domain.Repo.AddSchedule(doctorID, startTime, endTime, capacity, appointmentType)
 }
 
-func (as *appointmentService) Book(doctorName string, userID int, startTime time.Time) (int, errors.AppointmentErr) {
-	var appointmentID int
+// Book books the slot for the Patient account userID, or for its dependent
+// dependentID if given, with the reason for the visit. The booking is only
+// requested if the Doctor approves bookings.
+func (as *appointmentService) Book(doctorName string, userID int, dependentID int, startTime time.Time, reason string) (domain.Booking, errors.AppointmentErr) {
+	var booking domain.Booking
+
+	// Get Patient being seen
+	patientID, err := patientFor(userID, dependentID)
+	if err != nil {
+		return booking, err
+	}
 
 	// Get DoctorID for given DoctorName
 	doctorID, err := domain.Repo.GetDoctorID(doctorName)
 	if err != nil {
-		return appointmentID, err
+		return booking, err
 	}
 
-	// Check If Appointment slot is available
-	slotAvailable, err := domain.Repo.CheckSlotAvailable(doctorID, startTime)
+	// Check If Appointment slot can be booked
+	if err := checkSlot(doctorID, startTime); err != nil {
+		return booking, err
+	}
+
+	// Check If Patient can take the Appointment
+	if err := checkPatient(patientID, doctorID, startTime); err != nil {
+		return booking, err
+	}
+
+	// Book
+	return bookSlot(doctorID, patientID, userID, startTime, reason)
+}
+
+// BookForPatient books the Patient into the schedule of the Doctor, by the
+// Doctor userID or one of their delegates, and notifies the Patient.
+func (as *appointmentService) BookForPatient(doctorName string, userID int, userType string, patientID int, startTime time.Time, reason string) (domain.Booking, errors.AppointmentErr) {
+	doctorID, err := domain.Repo.GetDoctorID(doctorName)
 	if err != nil {
-		return appointmentID, err
+		return domain.Booking{}, err
 	}
 
-	// Cant book
-	if !slotAvailable {
-		return appointmentID, errors.NewGeneralError(fmt.Sprintf("Slot already taken"), nil)
+	actingDoctorID, err := doctorOf(userID, userType)
+	if err != nil {
+		return domain.Booking{}, err
 	}
 
-	// Check If Appointment within Doctor schedule
-	slotWithinSchedule, err := domain.Repo.CheckSlotWithinSchedule(doctorID, startTime)
+	// Doctors can only book into their own schedule
+	if actingDoctorID != doctorID {
+		return domain.Booking{}, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	patient, err := domain.Repo.GetPatient(patientID)
 	if err != nil {
-		return appointmentID, err
+		return domain.Booking{}, err
 	}
 
-	if !slotWithinSchedule {
-		return appointmentID, errors.NewGeneralError(fmt.Sprintf("Slot not within schedule"), nil)
+	// The booking window applies to Patients only
+	if err := checkScheduledSlot(doctorID, startTime); err != nil {
+		return domain.Booking{}, err
 	}
 
-	// Book
-	appointmentID, err = domain.Repo.BookSlot(doctorID, userID, startTime)
+	if err := checkPatient(patient.ID, doctorID, startTime); err != nil {
+		return domain.Booking{}, err
+	}
+
+	// The account managing a dependent keeps managing its appointments
+	bookedBy := patient.ID
+	if patient.AccountID != 0 {
+		bookedBy = patient.AccountID
+	}
+
+	appointmentID, err := domain.Repo.BookSlotFor(doctorID, patient.ID, bookedBy, startTime, reason, userID, userType)
 	if err != nil {
-		return appointmentID, err
+		return domain.Booking{ID: appointmentID}, err
 	}
 
-	return appointmentID, nil
+	if err := requireDeposit(patient.ID, appointmentID); err != nil {
+		return domain.Booking{ID: appointmentID}, err
+	}
+
+	doctor, err := domain.Repo.GetDoctor(doctorID)
+	if err != nil {
+		return domain.Booking{ID: appointmentID}, err
+	}
+
+	notify(bookedBy, fmt.Sprintf("Doctor %s booked an appointment for %s at %s. Your appointment id is %d.", doctor.Name, patient.Name, startTime.UTC().Format(time.RFC3339), appointmentID))
+
+	return domain.Repo.GetBooking(appointmentID)
 }
 
-func (as *appointmentService) ListSchedule(doctorName string) ([]domain.Appointment, errors.AppointmentErr) {
-	appointments := make([]domain.Appointment, 0)
+// ListSchedule returns the page of slots of the Doctor schedule matching the
+// filter, how many match in total, and the summary of the date range.
+func (as *appointmentService) ListSchedule(doctorName string, filter domain.ScheduleFilter) ([]domain.Appointment, int, domain.ScheduleSummary, errors.AppointmentErr) {
+	summary := domain.ScheduleSummary{Days: make([]domain.DaySummary, 0)}
 
 	// Get DoctorID for given DoctorName
 	doctorID, err := domain.Repo.GetDoctorID(doctorName)
 	if err != nil {
-		return appointments, err
+		return nil, 0, summary, err
 	}
 
 	// List
-	appointments, err = domain.Repo.ListSchedule(doctorID)
+	appointments, err := domain.Repo.ListSchedule(doctorID, filter.From, filter.To)
+	if err != nil {
+		return nil, 0, summary, err
+	}
+
+	if len(appointments) == 0 {
+		return appointments, 0, summary, nil
+	}
+
+	// Suppress slots on holidays and outside the booking window
+	holidays, err := domain.Repo.GetHolidays(doctorID, appointments[0].StartTime, appointments[len(appointments)-1].StartTime)
+	if err != nil {
+		return nil, 0, summary, err
+	}
+
+	settings, err := domain.Repo.GetDoctorSettings(doctorID)
 	if err != nil {
-		return appointments, err
+		return nil, 0, summary, err
+	}
+
+	now := time.Now()
+	available := make([]domain.Appointment, 0, len(appointments))
+
+	for _, appointment := range appointments {
+		if _, ok := holidays[appointment.StartTime.Format(domain.DateFormat)]; ok {
+			continue
+		}
+
+		// Hide free slots that can no longer or not yet be booked
+		if appointment.Remaining == appointment.Capacity && checkBookingWindow(settings, appointment.StartTime, now) != nil {
+			continue
+		}
+
+		if len(filter.AppointmentType) != 0 && !strings.EqualFold(appointment.AppointmentType, filter.AppointmentType) {
+			continue
+		}
+
+		summarize(&summary, appointment)
+
+		if (filter.Only == domain.SlotsFree && appointment.Booked) || (filter.Only == domain.SlotsBooked && !appointment.Booked) {
+			continue
+		}
+
+		available = append(available, appointment)
 	}
 
-	return appointments, nil
+	return page(available, filter.Offset, filter.Limit), len(available), summary, nil
 }
 
-func (as *appointmentService) Cancel(appointID int, userID int, userType string) errors.AppointmentErr {
+// summarize counts the slot in the summary, and in the summary of its day.
+func summarize(summary *domain.ScheduleSummary, appointment domain.Appointment) {
+	date := appointment.StartTime.UTC().Format(domain.DateFormat)
+
+	if n := len(summary.Days); n == 0 || summary.Days[n-1].Date != date {
+		summary.Days = append(summary.Days, domain.DaySummary{Date: date})
+	}
+
+	day := &summary.Days[len(summary.Days)-1]
+
+	summary.Slots++
+	day.Slots++
+
+	if appointment.Booked {
+		summary.Booked++
+		day.Booked++
+	} else {
+		summary.Free++
+		day.Free++
+	}
+}
+
+// page returns the limit slots from offset, or all of them from offset if
+// limit is 0.
+func page(appointments []domain.Appointment, offset int, limit int) []domain.Appointment {
+	if offset >= len(appointments) {
+		return appointments[:0]
+	}
+
+	appointments = appointments[offset:]
+
+	if limit > 0 && limit < len(appointments) {
+		appointments = appointments[:limit]
+	}
+
+	return appointments
+}
+
+// Cancel cancels the appointment with the reason given. Patients cannot cancel
+// once the appointment has started, and cancellations within the Doctor
+// cutoff are flagged as late.
+func (as *appointmentService) Cancel(appointID int, userID int, userType string, reason string) errors.AppointmentErr {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		if err.GetStatus() == http.StatusNotFound {
+			return errors.NewGeneralError(err.GetMessage(), nil)
+		}
+
+		return err
+	}
+
+	if !canManage(booking, userID, userType) {
+		return errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	late, err := checkCancel(booking, userType, reason, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Check If Appointment id exists and active
-	err := domain.Repo.CancelAppointment(appointID, userID, userType)
+	err = domain.Repo.CancelAppointment(appointID, userID, userType, strings.TrimSpace(reason), late)
+	if err != nil {
+		return err
+	}
+
+	// Offer freed slot to waitlist
+	promoteWaitlist(booking.DoctorID, booking.StartTime)
+
+	return nil
+}
+
+// checkCancel checks that the user can cancel the booking at now with the
+// reason given, and tells whether the cancellation is late.
+func checkCancel(booking domain.Booking, userType string, reason string, now time.Time) (bool, errors.AppointmentErr) {
+	settings, err := domain.Repo.GetDoctorSettings(booking.DoctorID)
+	if err != nil {
+		return false, err
+	}
+
+	if settings.RequireCancellationReason && len(strings.TrimSpace(reason)) == 0 {
+		return false, errors.NewGeneralError("Cancellation reason is required", nil)
+	}
+
+	if userType == "patient" && booking.Active && !now.Before(booking.StartTime) {
+		return false, errors.NewGeneralError("Appointment has already started and cannot be cancelled", nil)
+	}
+
+	return isLateCancellation(settings, booking.StartTime, now), nil
+}
+
+// GetAppointment returns the appointment to the users who can manage it.
+func (as *appointmentService) GetAppointment(appointID int, userID int, userType string) (domain.Booking, errors.AppointmentErr) {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return booking, err
+	}
+
+	if !canManage(booking, userID, userType) {
+		return domain.Booking{}, errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	return booking, nil
+}
+
+// Reschedule moves the appointment to startTime, with the Doctor named
+// doctorName or with the same Doctor if no name is given.
+func (as *appointmentService) Reschedule(appointID int, userID int, userType string, doctorName string, startTime time.Time) errors.AppointmentErr {
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return err
+	}
+
+	if !canManage(booking, userID, userType) {
+		return errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+	}
+
+	if !booking.Open() {
+		return errors.NewGeneralError(fmt.Sprintf("appointment id %d is %s and cannot be rescheduled", appointID, booking.Status), nil)
+	}
+
+	doctorID := booking.DoctorID
+
+	if len(doctorName) != 0 {
+		doctorID, err = domain.Repo.GetDoctorID(doctorName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if doctorID == booking.DoctorID && startTime.Equal(booking.StartTime) {
+		return errors.NewGeneralError("Appointment is already booked for this slot", nil)
+	}
+
+	// Check If new slot can be booked
+	if err := checkSlot(doctorID, startTime); err != nil {
+		return err
+	}
+
+	if err := checkPatient(booking.PatientID, doctorID, startTime, booking.ID); err != nil {
+		return err
+	}
+
+	expiresAt, err := moveExpiry(doctorID, startTime, userID, userType)
+	if err != nil {
+		return err
+	}
+
+	// Move
+	err = domain.Repo.RescheduleAppointment(appointID, doctorID, startTime, userID, userType, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	// Offer freed slot to waitlist
+	promoteWaitlist(booking.DoctorID, booking.StartTime)
+
+	return nil
+}
+
+// canManage checks if the user is allowed to change the appointment, which is
+// the case for its Doctor and their delegates, its Patient or their account,
+// and Admins.
+func canManage(booking domain.Booking, userID int, userType string) bool {
+	switch userType {
+	case "doctor":
+		return userID == booking.DoctorID
+	case "delegate":
+		doctorID, err := domain.Repo.GetDelegateDoctor(userID)
+
+		return err == nil && doctorID == booking.DoctorID
+	case "patient":
+		return booking.ManagedBy(userID)
+	case "admin":
+		return isAdmin(userID)
+	}
+
+	return false
+}
+
+// checkDoctorAccess refuses users other than the Doctor of the appointment,
+// their delegates and Admins.
+func checkDoctorAccess(booking domain.Booking, userID int, userType string) errors.AppointmentErr {
+	switch userType {
+	case "doctor":
+		if userID == booking.DoctorID {
+			return nil
+		}
+	case "delegate":
+		doctorID, err := domain.Repo.GetDelegateDoctor(userID)
+		if err != nil {
+			return err
+		}
+
+		if doctorID == booking.DoctorID {
+			return nil
+		}
+	case "admin":
+		if isAdmin(userID) {
+			return nil
+		}
+	}
+
+	return errors.NewGeneralForbiddenError("unauthorised to perform this action", nil)
+}
+
+// isAdmin checks the Admin account of the token still exists, rather than
+// trusting the user type alone.
+func isAdmin(userID int) bool {
+	exists, err := domain.Repo.AdminExists(userID)
+
+	return err == nil && exists
+}
+
+// checkSlot checks that the slot of the Doctor can be booked at startTime.
+func checkSlot(doctorID int, startTime time.Time) errors.AppointmentErr {
+	// Check If Appointment is within the Doctor booking window
+	settings, err := domain.Repo.GetDoctorSettings(doctorID)
 	if err != nil {
 		return err
 	}
 
+	if err := checkBookingWindow(settings, startTime, time.Now()); err != nil {
+		return err
+	}
+
+	return checkScheduledSlot(doctorID, startTime)
+}
+
+// checkScheduledSlot checks that the slot of the Doctor at startTime is free
+// and within their schedule, outside holidays.
+func checkScheduledSlot(doctorID int, startTime time.Time) errors.AppointmentErr {
+	// Check If Doctor is off for a holiday
+	if err := checkHoliday(doctorID, startTime); err != nil {
+		return err
+	}
+
+	// Check If Appointment slot is available
+	slotAvailable, err := domain.Repo.CheckSlotAvailable(doctorID, startTime)
+	if err != nil {
+		return err
+	}
+
+	// Cant book
+	if !slotAvailable {
+		return errors.NewConflictError("Slot already taken", nil)
+	}
+
+	// Check If Appointment within Doctor schedule
+	slotWithinSchedule, err := domain.Repo.CheckSlotWithinSchedule(doctorID, startTime)
+	if err != nil {
+		return err
+	}
+
+	if !slotWithinSchedule {
+		return errors.NewGeneralError(fmt.Sprintf("Slot not within schedule"), nil)
+	}
+
 	return nil
 }
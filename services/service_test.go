@@ -0,0 +1,391 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// useTestRepo points domain.Repo at a new database for the test, and returns
+// the database. Tests using it cannot run in parallel.
+func useTestRepo(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "appointments.db")+"?_txlock=immediate&_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening database", err)
+	}
+
+	if err := domain.AutoMigrate(db); err != nil {
+		t.Fatalf("an error '%s' was not expected when migrating database", err)
+	}
+
+	repo := domain.Repo
+	domain.Repo = domain.NewAppointmentRepository(db)
+
+	t.Cleanup(func() {
+		domain.Repo = repo
+		db.Close()
+	})
+
+	return db
+}
+
+// addDoctor creates the Doctor with an hour of schedule from each start time.
+func addDoctor(t *testing.T, name string, capacity int, startTimes ...time.Time) int {
+	t.Helper()
+
+	doctorID, err := domain.Repo.CreateDoctorAccount(domain.Doctor{Name: name})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating doctor", err.GetMessage())
+	}
+
+	for _, startTime := range startTimes {
+		if _, err := AppointmentService.AddSchedule(doctorID, startTime, startTime.Add(time.Hour), capacity, ""); err != nil {
+			t.Fatalf("an error '%s' was not expected when adding schedule", err.GetMessage())
+		}
+	}
+
+	return doctorID
+}
+
+// addPatient creates the Patient account.
+func addPatient(t *testing.T, name string) int {
+	t.Helper()
+
+	patientID, err := domain.Repo.CreatePatientAccount(name, "")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating patient", err.GetMessage())
+	}
+
+	return patientID
+}
+
+// weekly returns count start times a week apart from tomorrow at 10:00 UTC.
+func weekly(count int) []time.Time {
+	start := time.Now().UTC().Truncate(24 * time.Hour).Add(34 * time.Hour)
+	startTimes := make([]time.Time, 0, count)
+
+	for i := 0; i < count; i++ {
+		startTimes = append(startTimes, start.AddDate(0, 0, 7*i))
+	}
+
+	return startTimes
+}
+
+func TestReschedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		approval     bool
+		requested    bool
+		otherDoctor  bool
+		userType     string
+		wantStatus   string
+		wantExpiring bool
+	}{
+		{
+			name:       "No Approval",
+			userType:   "patient",
+			wantStatus: domain.StatusConfirmed,
+		},
+		{
+			// The Doctor has to approve the new slot
+			name:         "Approval Required",
+			approval:     true,
+			userType:     "patient",
+			wantStatus:   domain.StatusRequested,
+			wantExpiring: true,
+		},
+		{
+			name:       "Approval Required Moved By Doctor",
+			approval:   true,
+			userType:   "doctor",
+			wantStatus: domain.StatusConfirmed,
+		},
+		{
+			// Moving a request approves it, without waiting to expire
+			name:       "Request Moved By Doctor",
+			approval:   true,
+			requested:  true,
+			userType:   "doctor",
+			wantStatus: domain.StatusConfirmed,
+		},
+		{
+			name:        "Request Moved To Doctor Without Approval",
+			approval:    true,
+			requested:   true,
+			otherDoctor: true,
+			userType:    "patient",
+			wantStatus:  domain.StatusConfirmed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			patientID := addPatient(t, "Patient1")
+
+			bookSlot := domain.Repo.BookSlot
+			if tt.requested {
+				bookSlot = func(doctorID int, patientID int, bookedBy int, startTime time.Time, reason string) (int, errors.AppointmentErr) {
+					return domain.Repo.RequestSlot(doctorID, patientID, bookedBy, startTime, reason, startTime)
+				}
+			}
+
+			appointID, err := bookSlot(doctorID, patientID, patientID, startTimes[0], "")
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+			}
+
+			if err := domain.Repo.SaveDoctorSettings(domain.DoctorSettings{DoctorID: doctorID, ApprovalRequired: tt.approval, RequestExpiryHours: domain.DefaultRequestExpiryHours}); err != nil {
+				t.Fatalf("an error '%s' was not expected when saving settings", err.GetMessage())
+			}
+
+			doctorName := ""
+			if tt.otherDoctor {
+				doctorName = "Doctor2"
+				addDoctor(t, doctorName, 1, startTimes...)
+			}
+
+			userID := patientID
+			if tt.userType == "doctor" {
+				userID = doctorID
+			}
+
+			if err := AppointmentService.Reschedule(appointID, userID, tt.userType, doctorName, startTimes[0].Add(15*time.Minute)); err != nil {
+				t.Fatalf("Reschedule() error = %s", err.GetMessage())
+			}
+
+			booking, err := domain.Repo.GetBooking(appointID)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when getting booking", err.GetMessage())
+			}
+
+			if booking.Status != tt.wantStatus {
+				t.Errorf("Reschedule() status = %s, want %s", booking.Status, tt.wantStatus)
+			}
+
+			if (booking.RequestExpiresAt != nil) != tt.wantExpiring {
+				t.Errorf("Reschedule() request expires at %v, want expiring %v", booking.RequestExpiresAt, tt.wantExpiring)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2021, 7, 18, 23, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		slots []domain.Appointment
+		want  domain.ScheduleSummary
+	}{
+		{
+			name: "No Slots",
+		},
+		{
+			name: "Group Slots",
+			slots: []domain.Appointment{
+				{StartTime: day, Capacity: 3, Remaining: 3},
+				{StartTime: day, Capacity: 3, Remaining: 1},
+				{StartTime: day, Capacity: 3, Remaining: 0, Booked: true},
+			},
+			want: domain.ScheduleSummary{Slots: 3, Free: 2, Booked: 1, Days: []domain.DaySummary{
+				{Date: "2021-07-18", Slots: 3, Free: 2, Booked: 1},
+			}},
+		},
+		{
+			// Days are UTC days, whatever the zone of the slot
+			name: "Day Rollover",
+			slots: []domain.Appointment{
+				{StartTime: day, Capacity: 1, Remaining: 0, Booked: true},
+				{StartTime: day.Add(15 * time.Minute).In(time.FixedZone("UTC-2", -2*60*60)), Capacity: 1, Remaining: 1},
+				{StartTime: day.Add(30 * time.Minute), Capacity: 1, Remaining: 1},
+			},
+			want: domain.ScheduleSummary{Slots: 3, Free: 2, Booked: 1, Days: []domain.DaySummary{
+				{Date: "2021-07-18", Slots: 1, Free: 0, Booked: 1},
+				{Date: "2021-07-19", Slots: 2, Free: 2, Booked: 0},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var summary domain.ScheduleSummary
+
+			for _, slot := range tt.slots {
+				summarize(&summary, slot)
+			}
+
+			if !reflect.DeepEqual(summary, tt.want) {
+				t.Errorf("summarize() = %+v, want %+v", summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	slots := make([]domain.Appointment, 5)
+	for i := range slots {
+		slots[i].ID = strconv.Itoa(i + 1)
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		limit  int
+		want   []string
+	}{
+		{
+			name:  "First Page",
+			limit: 2,
+			want:  []string{"1", "2"},
+		},
+		{
+			name:   "Last Page",
+			offset: 4,
+			limit:  2,
+			want:   []string{"5"},
+		},
+		{
+			name:   "Limit 0",
+			offset: 1,
+			want:   []string{"2", "3", "4", "5"},
+		},
+		{
+			name:   "Offset At End",
+			offset: 5,
+			limit:  2,
+			want:   []string{},
+		},
+		{
+			name:   "Offset Past End",
+			offset: 9,
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]string, 0)
+			for _, slot := range page(slots, tt.offset, tt.limit) {
+				got = append(got, slot.ID)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("page(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookForPatient(t *testing.T) {
+	tests := []struct {
+		name        string
+		userType    string
+		dependent   bool
+		otherDoctor bool
+		wantStatus  int
+	}{
+		{
+			name:     "Doctor",
+			userType: "doctor",
+		},
+		{
+			name:     "Delegate",
+			userType: "delegate",
+		},
+		{
+			// The account holder keeps managing the appointment
+			name:      "Dependent",
+			userType:  "doctor",
+			dependent: true,
+		},
+		{
+			name:        "Other Doctor",
+			userType:    "doctor",
+			otherDoctor: true,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:       "Patient",
+			userType:   "patient",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTimes := weekly(1)
+			doctorID := addDoctor(t, "Doctor1", 1, startTimes...)
+			accountID := addPatient(t, "Patient1")
+
+			patientID := accountID
+			if tt.dependent {
+				var err errors.AppointmentErr
+
+				if patientID, err = domain.Repo.CreateDependent(accountID, "Dependent1"); err != nil {
+					t.Fatalf("an error '%s' was not expected when creating dependent", err.GetMessage())
+				}
+			}
+
+			userID := doctorID
+			switch {
+			case tt.otherDoctor:
+				userID = addDoctor(t, "Doctor2", 1)
+			case tt.userType == "delegate":
+				var err errors.AppointmentErr
+
+				if userID, err = domain.Repo.CreateDelegateAccount(doctorID, "Delegate1"); err != nil {
+					t.Fatalf("an error '%s' was not expected when creating delegate", err.GetMessage())
+				}
+			case tt.userType == "patient":
+				userID = accountID
+			}
+
+			booking, err := AppointmentService.BookForPatient("Doctor1", userID, tt.userType, patientID, startTimes[0], "")
+			if tt.wantStatus != 0 {
+				if err == nil || err.GetStatus() != tt.wantStatus {
+					t.Fatalf("BookForPatient() error = %v, want status %d", err, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("BookForPatient() error = %s", err.GetMessage())
+			}
+
+			if booking.PatientID != patientID || booking.BookedBy != accountID || booking.Status != domain.StatusConfirmed {
+				t.Errorf("BookForPatient() = %+v, want patient %d booked by %d", booking, patientID, accountID)
+			}
+
+			if !booking.DoctorInitiated || booking.InitiatedBy != userID || booking.InitiatedByType != tt.userType {
+				t.Errorf("BookForPatient() initiated by %d %q, want %d %q", booking.InitiatedBy, booking.InitiatedByType, userID, tt.userType)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+func (as *appointmentService) GetDoctorSettings(doctorID int) (domain.DoctorSettings, errors.AppointmentErr) {
+	return domain.Repo.GetDoctorSettings(doctorID)
+}
+
+func (as *appointmentService) UpdateDoctorSettings(settings domain.DoctorSettings) errors.AppointmentErr {
+	if settings.MinNoticeMinutes < 0 || settings.MaxAdvanceDays < 0 || settings.CancellationCutoffMinutes < 0 {
+		return errors.NewGeneralError("Settings cannot be negative", nil)
+	}
+
+	if settings.RequestExpiryHours < 1 {
+		return errors.NewGeneralError("Requests must be given at least an hour to be approved", nil)
+	}
+
+	if settings.MaxAdvanceDays != 0 && time.Duration(settings.MinNoticeMinutes)*time.Minute >= time.Duration(settings.MaxAdvanceDays)*24*time.Hour {
+		return errors.NewGeneralError("Minimum notice must be shorter than maximum advance booking", nil)
+	}
+
+	return domain.Repo.SaveDoctorSettings(settings)
+}
+
+// checkBookingWindow refuses start times that are too close to now or too
+// far ahead for the Doctor settings.
+func checkBookingWindow(settings domain.DoctorSettings, startTime time.Time, now time.Time) errors.AppointmentErr {
+	minNotice := time.Duration(settings.MinNoticeMinutes) * time.Minute
+
+	if startTime.Before(now.Add(minNotice)) {
+		return errors.NewGeneralError(fmt.Sprintf("Appointments must be booked at least %d minutes in advance", settings.MinNoticeMinutes), nil)
+	}
+
+	if settings.MaxAdvanceDays > 0 && startTime.After(now.AddDate(0, 0, settings.MaxAdvanceDays)) {
+		return errors.NewGeneralError(fmt.Sprintf("Appointments cannot be booked more than %d days in advance", settings.MaxAdvanceDays), nil)
+	}
+
+	return nil
+}
+
+// isLateCancellation checks if cancelling at now falls within the Doctor
+// cancellation cutoff, or after the appointment has started.
+func isLateCancellation(settings domain.DoctorSettings, startTime time.Time, now time.Time) bool {
+	cutoff := time.Duration(settings.CancellationCutoffMinutes) * time.Minute
+
+	return !now.Before(startTime.Add(-cutoff))
+}
+
+// requestExpiry is when a booking request made at now expires unless the
+// Doctor approves it, at the latest when the appointment starts.
+func requestExpiry(settings domain.DoctorSettings, startTime time.Time, now time.Time) time.Time {
+	expiresAt := now.Add(time.Duration(settings.RequestExpiryHours) * time.Hour)
+
+	if startTime.Before(expiresAt) {
+		return startTime
+	}
+
+	return expiresAt
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"appointment/domain"
+	"testing"
+	"time"
+)
+
+func TestCheckBookingWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 7, 18, 10, 0, 0, 0, time.UTC)
+	settings := domain.DoctorSettings{MinNoticeMinutes: 60, MaxAdvanceDays: 7}
+
+	tests := []struct {
+		name      string
+		settings  domain.DoctorSettings
+		startTime time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "OK",
+			settings:  settings,
+			startTime: now.Add(2 * time.Hour),
+		},
+		{
+			name:      "Short Notice",
+			settings:  settings,
+			startTime: now.Add(30 * time.Minute),
+			wantErr:   true,
+		},
+		{
+			name:      "Too Far Ahead",
+			settings:  settings,
+			startTime: now.AddDate(0, 0, 8),
+			wantErr:   true,
+		},
+		{
+			// No limit on advance booking by default
+			name:      "Defaults",
+			settings:  domain.DoctorSettings{},
+			startTime: now.AddDate(1, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBookingWindow(tt.settings, tt.startTime, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkBookingWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsLateCancellation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 7, 18, 10, 0, 0, 0, time.UTC)
+	settings := domain.DoctorSettings{CancellationCutoffMinutes: 24 * 60}
+
+	tests := []struct {
+		name      string
+		settings  domain.DoctorSettings
+		startTime time.Time
+		want      bool
+	}{
+		{
+			name:      "Before Cutoff",
+			settings:  settings,
+			startTime: now.AddDate(0, 0, 2),
+		},
+		{
+			name:      "Within Cutoff",
+			settings:  settings,
+			startTime: now.Add(3 * time.Hour),
+			want:      true,
+		},
+		{
+			// Without a cutoff only cancellations after the start are late
+			name:      "Defaults",
+			settings:  domain.DoctorSettings{},
+			startTime: now.Add(time.Minute),
+		},
+		{
+			name:      "Started",
+			settings:  domain.DoctorSettings{},
+			startTime: now.Add(-time.Minute),
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLateCancellation(tt.settings, tt.startTime, now); got != tt.want {
+				t.Errorf("isLateCancellation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 7, 18, 10, 0, 0, 0, time.UTC)
+	settings := domain.DoctorSettings{RequestExpiryHours: 24}
+
+	tests := []struct {
+		name      string
+		startTime time.Time
+		want      time.Time
+	}{
+		{
+			name:      "Expiry Hours",
+			startTime: now.AddDate(0, 0, 3),
+			want:      now.Add(24 * time.Hour),
+		},
+		{
+			// Requests cannot outlive the appointment
+			name:      "Starts Sooner",
+			startTime: now.Add(2 * time.Hour),
+			want:      now.Add(2 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestExpiry(settings, tt.startTime, now); !got.Equal(tt.want) {
+				t.Errorf("requestExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"time"
+)
+
+// UpdateStatus moves the appointment through its lifecycle on behalf of its
+// Doctor, their delegates or Admins, and returns the updated appointment.
+// Cancellations go through Cancel with the reason given so the slot is
+// offered to the waitlist.
+func (as *appointmentService) UpdateStatus(appointID int, userID int, userType string, status string, reason string) (domain.Booking, errors.AppointmentErr) {
+	if !domain.IsStatus(status) {
+		return domain.Booking{}, errors.NewGeneralError(fmt.Sprintf("Unknown status %s", status), nil)
+	}
+
+	booking, err := domain.Repo.GetBooking(appointID)
+	if err != nil {
+		return booking, err
+	}
+
+	if err := checkDoctorAccess(booking, userID, userType); err != nil {
+		return booking, err
+	}
+
+	if status == domain.StatusCancelled {
+		if err := as.Cancel(appointID, userID, userType, reason); err != nil {
+			return booking, err
+		}
+
+		return domain.Repo.GetBooking(appointID)
+	}
+
+	if !domain.CanTransition(booking.Status, status) {
+		return booking, errors.NewGeneralError(fmt.Sprintf("appointment id %d cannot move from %s to %s", appointID, booking.Status, status), nil)
+	}
+
+	if status == domain.StatusNoShow && time.Now().Before(booking.StartTime) {
+		return booking, errors.NewGeneralError("Appointment has not started yet", nil)
+	}
+
+	if err := domain.Repo.UpdateStatus(appointID, booking.Status, status, userID, userType); err != nil {
+		return booking, err
+	}
+
+	return domain.Repo.GetBooking(appointID)
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"appointment/domain"
+	"appointment/errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// JoinWaitlist puts the Patient on the waitlist of the Doctor, for the slot at
+// startTime or for any slot on date if startTime is nil.
+func (as *appointmentService) JoinWaitlist(patientID int, doctorName string, startTime *time.Time, date time.Time, autoBook bool) (domain.WaitlistEntry, errors.AppointmentErr) {
+	entry := domain.WaitlistEntry{PatientID: patientID, StartTime: startTime, AutoBook: autoBook, Status: domain.WaitlistWaiting}
+
+	doctorID, err := domain.Repo.GetDoctorID(doctorName)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.DoctorID = doctorID
+	entry.Date = date.Format(domain.DateFormat)
+
+	if startTime != nil {
+		entry.Date = startTime.Format(domain.DateFormat)
+
+		slotAvailable, err := domain.Repo.CheckSlotAvailable(doctorID, *startTime)
+		if err != nil {
+			return entry, err
+		}
+
+		if slotAvailable {
+			return entry, errors.NewGeneralError("Slot is available, book it instead", nil)
+		}
+	}
+
+	entry.ID, err = domain.Repo.AddToWaitlist(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	entries, err := domain.Repo.GetWaitlist(patientID)
+	if err != nil {
+		return entry, err
+	}
+
+	for _, e := range entries {
+		if e.ID == entry.ID {
+			return e, nil
+		}
+	}
+
+	return entry, nil
+}
+
+func (as *appointmentService) GetWaitlist(patientID int) ([]domain.WaitlistEntry, errors.AppointmentErr) {
+	return domain.Repo.GetWaitlist(patientID)
+}
+
+func (as *appointmentService) LeaveWaitlist(waitlistID int, patientID int) errors.AppointmentErr {
+	return domain.Repo.RemoveFromWaitlist(waitlistID, patientID)
+}
+
+func (as *appointmentService) GetNotifications(patientID int) ([]domain.Notification, errors.AppointmentErr) {
+	return domain.Repo.GetNotifications(patientID)
+}
+
+// promoteWaitlist hands a freed slot to the first eligible Patient on the
+// waitlist. Patients who asked for it are booked automatically, the others get
+// a hold on the slot and are notified. Failures are logged as the slot was
+// already freed.
+func promoteWaitlist(doctorID int, startTime time.Time) {
+	if startTime.Before(time.Now()) {
+		return
+	}
+
+	entries, err := domain.Repo.GetWaitingForSlot(doctorID, startTime)
+	if err != nil {
+		log.Printf("Error occured while fetching waitlist : %s\n", err.GetError())
+
+		return
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	doctor, err := domain.Repo.GetDoctor(doctorID)
+	if err != nil {
+		log.Printf("Error occured while fetching doctor for waitlist : %s\n", err.GetError())
+
+		return
+	}
+
+	slot := startTime.Format(time.RFC3339)
+
+	for _, entry := range entries {
+		if !entry.AutoBook {
+			claimed, err := domain.Repo.UpdateWaitlistStatus(entry.ID, domain.WaitlistWaiting, domain.WaitlistOffered, 0)
+			if err != nil || !claimed {
+				continue
+			}
+
+			// Hold the slot while the Patient decides
+			expiresAt := holdExpiry()
+
+			holdID, err := domain.Repo.HoldSlot(doctorID, entry.PatientID, startTime, expiresAt, entry.ID)
+			if err != nil {
+				domain.Repo.UpdateWaitlistStatus(entry.ID, domain.WaitlistOffered, domain.WaitlistWaiting, 0)
+
+				if err.GetStatus() == http.StatusConflict {
+					return
+				}
+
+				continue
+			}
+
+			notify(entry.PatientID, fmt.Sprintf("A slot with Doctor %s at %s is held for you until %s. Confirm it on /hold/confirm with hold id %d.", doctor.Name, slot, expiresAt.UTC().Format(time.RFC3339), holdID))
+
+			return
+		}
+
+		claimed, err := domain.Repo.UpdateWaitlistStatus(entry.ID, domain.WaitlistWaiting, domain.WaitlistBooked, 0)
+		if err != nil || !claimed {
+			continue
+		}
+
+		booking, err := bookFromWaitlist(entry, startTime)
+		if err != nil {
+			domain.Repo.UpdateWaitlistStatus(entry.ID, domain.WaitlistBooked, domain.WaitlistWaiting, 0)
+
+			// Someone else got the slot first
+			if err.GetStatus() == http.StatusConflict {
+				return
+			}
+
+			continue
+		}
+
+		domain.Repo.UpdateWaitlistStatus(entry.ID, domain.WaitlistBooked, domain.WaitlistBooked, booking.ID)
+
+		if booking.Status == domain.StatusRequested {
+			notify(entry.PatientID, fmt.Sprintf("You have been booked from the waitlist with Doctor %s at %s, pending the approval of the Doctor. Your appointment id is %d.", doctor.Name, slot, booking.ID))
+
+			return
+		}
+
+		notify(entry.PatientID, fmt.Sprintf("You have been booked from the waitlist with Doctor %s at %s. Your appointment id is %d.", doctor.Name, slot, booking.ID))
+
+		return
+	}
+}
+
+func bookFromWaitlist(entry domain.WaitlistEntry, startTime time.Time) (domain.Booking, errors.AppointmentErr) {
+	if err := checkSlot(entry.DoctorID, startTime); err != nil {
+		return domain.Booking{}, err
+	}
+
+	if err := checkPatient(entry.PatientID, entry.DoctorID, startTime); err != nil {
+		return domain.Booking{}, err
+	}
+
+	return bookSlot(entry.DoctorID, entry.PatientID, entry.PatientID, startTime, "")
+}
+
+func notify(patientID int, message string) {
+	if err := domain.Repo.AddNotification(patientID, message); err != nil {
+		log.Printf("Error occured while notifying patient %d : %s\n", patientID, err.GetError())
+	}
+}
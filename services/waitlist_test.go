@@ -0,0 +1,170 @@
+package services
+
+import (
+	"appointment/domain"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// wait is a waitlist entry for the slot of the index, or for any slot of the
+// day if the index is -1.
+type wait struct {
+	slot     int
+	autoBook bool
+}
+
+// joinWaitlist books the first two slots of Doctor1 from startTime for others,
+// and puts a new Patient on the waitlist for each wait, in order.
+func joinWaitlist(t *testing.T, startTime time.Time, waits []wait) (doctorID int, bookerID int, appointIDs []int, patientIDs []int) {
+	t.Helper()
+
+	doctorID = addDoctor(t, "Doctor1", 1, startTime)
+	bookerID = addPatient(t, "Booker")
+	slots := []time.Time{startTime, startTime.Add(15 * time.Minute)}
+
+	for _, slot := range slots {
+		appointID, err := domain.Repo.BookSlot(doctorID, bookerID, bookerID, slot, "")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when booking", err.GetMessage())
+		}
+
+		appointIDs = append(appointIDs, appointID)
+	}
+
+	for i, w := range waits {
+		patientID := addPatient(t, fmt.Sprintf("Patient%d", i+1))
+
+		var slot *time.Time
+		if w.slot >= 0 {
+			slot = &slots[w.slot]
+		}
+
+		if _, err := AppointmentService.JoinWaitlist(patientID, "Doctor1", slot, startTime, w.autoBook); err != nil {
+			t.Fatalf("an error '%s' was not expected when joining waitlist", err.GetMessage())
+		}
+
+		patientIDs = append(patientIDs, patientID)
+	}
+
+	return doctorID, bookerID, appointIDs, patientIDs
+}
+
+func TestWaitlistPositions(t *testing.T) {
+	useTestRepo(t)
+
+	// Entries for any slot of the day wait for every slot
+	waits := []wait{{slot: 0}, {slot: -1}, {slot: 1}, {slot: 0}}
+	wantPositions := []int{1, 2, 2, 3}
+
+	_, _, _, patientIDs := joinWaitlist(t, weekly(1)[0], waits)
+
+	for i, patientID := range patientIDs {
+		entries, err := AppointmentService.GetWaitlist(patientID)
+		if err != nil {
+			t.Fatalf("GetWaitlist() error = %s", err.GetMessage())
+		}
+
+		if len(entries) != 1 || entries[0].Position != wantPositions[i] {
+			t.Errorf("GetWaitlist() of patient %d = %+v, want position %d", i+1, entries, wantPositions[i])
+		}
+	}
+}
+
+func TestPromoteWaitlist(t *testing.T) {
+	tests := []struct {
+		name         string
+		waits        []wait
+		wantStatuses []string
+		wantBooked   int
+		// The cancelled slot is left free only when nobody waits for it
+		wantAvailable bool
+	}{
+		{
+			// The first Patient waiting gets a hold on the slot
+			name:         "Offer",
+			waits:        []wait{{slot: 0}, {slot: 0}},
+			wantStatuses: []string{domain.WaitlistOffered, domain.WaitlistWaiting},
+			wantBooked:   -1,
+		},
+		{
+			name:         "Auto Book",
+			waits:        []wait{{slot: 0, autoBook: true}, {slot: 0, autoBook: true}},
+			wantStatuses: []string{domain.WaitlistBooked, domain.WaitlistWaiting},
+			wantBooked:   0,
+		},
+		{
+			name:         "Any Slot Of Day",
+			waits:        []wait{{slot: -1, autoBook: true}},
+			wantStatuses: []string{domain.WaitlistBooked},
+			wantBooked:   0,
+		},
+		{
+			// Entries for other slots keep waiting, in order
+			name:         "Order",
+			waits:        []wait{{slot: 1, autoBook: true}, {slot: 0}, {slot: -1, autoBook: true}},
+			wantStatuses: []string{domain.WaitlistWaiting, domain.WaitlistOffered, domain.WaitlistWaiting},
+			wantBooked:   -1,
+		},
+		{
+			name:          "Other Slot",
+			waits:         []wait{{slot: 1, autoBook: true}},
+			wantStatuses:  []string{domain.WaitlistWaiting},
+			wantBooked:    -1,
+			wantAvailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRepo(t)
+
+			startTime := weekly(1)[0]
+			doctorID, bookerID, appointIDs, patientIDs := joinWaitlist(t, startTime, tt.waits)
+
+			if err := AppointmentService.Cancel(appointIDs[0], bookerID, "patient", ""); err != nil {
+				t.Fatalf("an error '%s' was not expected when cancelling", err.GetMessage())
+			}
+
+			for i, patientID := range patientIDs {
+				entries, err := AppointmentService.GetWaitlist(patientID)
+				if err != nil {
+					t.Fatalf("GetWaitlist() error = %s", err.GetMessage())
+				}
+
+				if len(entries) != 1 || entries[0].Status != tt.wantStatuses[i] {
+					t.Errorf("waitlist of patient %d = %+v, want status %s", i+1, entries, tt.wantStatuses[i])
+
+					continue
+				}
+
+				bookings, err := domain.Repo.GetPatientBookings(patientID, time.Now(), farFuture)
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when getting bookings", err.GetMessage())
+				}
+
+				if i != tt.wantBooked {
+					if len(bookings) != 0 {
+						t.Errorf("patient %d booked %+v, want none", i+1, bookings)
+					}
+
+					continue
+				}
+
+				if len(bookings) != 1 || !bookings[0].StartTime.Equal(startTime) || entries[0].AppointmentID != bookings[0].ID {
+					t.Errorf("patient %d booked %+v from %+v, want the slot at %s", i+1, bookings, entries[0], startTime)
+				}
+			}
+
+			// Offered slots are held, so nobody else can take them meanwhile
+			available, err := domain.Repo.CheckSlotAvailable(doctorID, startTime)
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when checking slot", err.GetMessage())
+			}
+
+			if available != tt.wantAvailable {
+				t.Errorf("slot available = %v, want %v", available, tt.wantAvailable)
+			}
+		})
+	}
+}
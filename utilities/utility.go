@@ -1,15 +1,38 @@
 package utilities
 
 import (
+	"appointment/config"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"strings"
 )
 
+// NewToken encodes the "id|type" data of a user into a token, signed so that
+// it cannot be made up or changed without the TOKEN_SECRET.
+func NewToken(data string) string {
+	code := GetCode(data)
+
+	return code + "." + base64.RawURLEncoding.EncodeToString(sign(code))
+}
+
+// ParseToken gets the user ID and type from the token, after checking its
+// signature.
 func ParseToken(token string) (string, string, error) {
 	var userName, userType string
 
-	text, err := ParseCode(token)
+	code, signature := token, ""
+	if i := strings.LastIndex(token, "."); i >= 0 {
+		code, signature = token[:i], token[i+1:]
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || len(signature) == 0 || !hmac.Equal(mac, sign(code)) {
+		return userName, userType, fmt.Errorf("invalid token signature")
+	}
+
+	text, err := ParseCode(code)
 	if err != nil {
 		return userName, userType, err
 	}
@@ -38,3 +61,10 @@ func ParseCode(code string) (string, error) {
 
 	return string(s), nil
 }
+
+func sign(code string) []byte {
+	mac := hmac.New(sha256.New, config.TokenSecret())
+	mac.Write([]byte(code))
+
+	return mac.Sum(nil)
+}
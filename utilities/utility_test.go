@@ -0,0 +1,53 @@
+package utilities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseToken(t *testing.T) {
+	t.Parallel()
+
+	token := NewToken("1|Doctor")
+	signature := token[strings.LastIndex(token, "."):]
+
+	tests := []struct {
+		name     string
+		token    string
+		wantID   string
+		wantType string
+		wantErr  bool
+	}{
+		{
+			name:     "OK",
+			token:    token,
+			wantID:   "1",
+			wantType: "Doctor",
+		},
+		{
+			// Signatures do not carry over to other users
+			name:    "Changed Type",
+			token:   GetCode("1|Admin") + signature,
+			wantErr: true,
+		},
+		{
+			name:    "Unsigned",
+			token:   GetCode("1|Admin"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, userType, err := ParseToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if err == nil && (id != tt.wantID || userType != tt.wantType) {
+				t.Errorf("ParseToken() = %v, %v, want %v, %v", id, userType, tt.wantID, tt.wantType)
+			}
+		})
+	}
+}